@@ -56,6 +56,9 @@ More on https://github.com/corneliusweig/rakkess/blob/v0.5.0/doc/USAGE.md#usage
 
   Review access to a config-map with a specific name
    $ rakkess for cm config-map-name --verbs=all
+
+  Visualise which bindings and roles grant access to secrets
+   $ rakkess for secrets -o dot | dot -Tsvg > secrets.svg
 `
 )
 
@@ -23,16 +23,22 @@ import (
 	"strings"
 
 	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/client/result"
 	"github.com/corneliusweig/rakkess/internal/constants"
 	"github.com/corneliusweig/rakkess/internal/diff"
+	"github.com/corneliusweig/rakkess/internal/graph"
 	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/corneliusweig/rakkess/internal/validation"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 )
 
 var (
 	opts     = options.NewRakkessOptions()
 	diffWith []string
+	expect   string
 )
 
 const (
@@ -72,8 +78,54 @@ More on https://github.com/corneliusweig/rakkess/blob/v0.5.0/doc/USAGE.md#usage
   Review access for different verbs
    $ rakkess --verbs get,watch,patch
 
+  Review all verbs with one line per resource
+   $ rakkess --verbs all -o compact
+
+  Review access with API group, version, and kind of each resource
+   $ rakkess -o wide
+
+  Review access grouped by API group, most permissive resources first
+   $ rakkess --group-by api-group --sort-by allowed-count
+
+  Review which bindings grant access in a namespace
+   $ rakkess --show-reasons -n default
+
+  Review access with colors in a pager
+   $ rakkess --color=always | less -R
+
+  Review access with custom symbols
+   $ rakkess --theme allowed=+:green,denied=-:red
+
+  Process results while they arrive
+   $ rakkess --stream | jq -c 'select(.state == "allowed")'
+
+  Review how broad the access of a service account is
+   $ rakkess --sa kube-system:namespace-controller --summary-only
+
+  Review all verbs for a few resources, with verbs as rows
+   $ rakkess --verbs all --transpose --rotate-headers -n default
+
   Review access rights diff with another service account
    $ rakkess --diff-with sa=kube-system:namespace-controller
+
+  Fail a CI job if the access of a service account deviates from a recorded access matrix
+   $ rakkess --sa ci:deployer -o yaml > expected.yaml
+   $ rakkess --sa ci:deployer --expect expected.yaml -o junit > report.xml
+
+  Review access to subresources such as pods/exec and pods/log
+   $ rakkess --include-subresources --verbs create,get -n default
+
+  Review access gently on a shared API server
+   $ rakkess --concurrency 4 --qps 20 --burst 40
+
+  Export the access matrix of a service-account as yaml
+   $ rakkess --sa kube-system:namespace-controller -o yaml
+
+  Export the access matrix of a service-account for the node-exporter textfile collector
+   $ rakkess --sa kube-system:namespace-controller -o prometheus > rakkess.prom
+
+  Print only resources which may be deleted
+   $ rakkess --verbs delete -o jsonpath='{range .status.cells[?(@.state=="allowed")]}{.resource} {.group}{"\n"}{end}'
 `
 )
 
@@ -85,17 +137,84 @@ var rootCmd = &cobra.Command{
 	Example: constants.HelpTextMapName(rakkessExamples),
 	Args:    cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if graph.IsFormat(opts.OutputFormat) {
+			return fmt.Errorf("output format %s is only supported by the 'for' command", opts.OutputFormat)
+		}
+		if opts.OutputFormat == "sarif" {
+			// risky grants involve any verb, e.g. get on secrets or patch on roles
+			if cmd.Flags().Changed(constants.FlagVerbs) && !sets.NewString(opts.Verbs...).HasAll(constants.ValidVerbs...) {
+				return fmt.Errorf("output format sarif checks all verbs, so --%s must be omitted or 'all'", constants.FlagVerbs)
+			}
+			opts.Verbs = constants.ValidVerbs
+		}
+		if opts.OutputFormat == "junit" && diffWith == nil && expect == "" {
+			return fmt.Errorf("output format junit requires --%s or --%s, because only changed or unexpected access rights fail the report", constants.FlagDiffWith, constants.FlagExpect)
+		}
+		if expect != "" {
+			if opts.OutputFormat != "junit" {
+				return fmt.Errorf("--%s requires output format junit", constants.FlagExpect)
+			}
+			if diffWith != nil {
+				return fmt.Errorf("--%s cannot be combined with --%s", constants.FlagExpect, constants.FlagDiffWith)
+			}
+		}
+		if diffWith != nil && !printer.IsTable(opts.OutputFormat) {
+			return fmt.Errorf("output format %s cannot be combined with --%s", opts.OutputFormat, constants.FlagDiffWith)
+		}
+		if opts.Summary || opts.SummaryOnly {
+			if diffWith != nil {
+				return fmt.Errorf("--%s cannot be combined with --%s", constants.FlagSummary, constants.FlagDiffWith)
+			}
+			if !printer.IsHumanReadable(opts.OutputFormat) {
+				return fmt.Errorf("output format %s cannot be combined with --%s", opts.OutputFormat, constants.FlagSummary)
+			}
+		}
+
+		if opts.Stream {
+			if diffWith != nil {
+				return fmt.Errorf("--%s cannot be combined with --%s", constants.FlagStream, constants.FlagDiffWith)
+			}
+			if cmd.Flags().Changed(constants.FlagOutput) {
+				return fmt.Errorf("--%s cannot be combined with --%s, because it always prints JSON lines", constants.FlagStream, constants.FlagOutput)
+			}
+		}
+
+		if err := validation.RateLimits(opts); err != nil {
+			return err
+		}
+		if err := validation.Retries(opts); err != nil {
+			return err
+		}
+
+		var expected *result.AccessMatrix
+		if expect != "" {
+			// fail before the access reviews, which may take a while
+			var err error
+			if expected, err = result.ReadMatrix(expect); err != nil {
+				return err
+			}
+		}
+
 		ctx, cancel := context.WithCancel(context.Background())
 		catchCtrlC(cancel)
 
+		if opts.Stream {
+			return rakkess.StreamResources(ctx, opts)
+		}
+
 		res, err := rakkess.Resource(ctx, opts)
 		if err != nil {
 			return err
 		}
+		if expected != nil {
+			return rakkess.PrintTable(opts, res.ExpectTable(opts.Verbs, expected))
+		}
 		if diffWith == nil {
-			t := res.Table(opts.Verbs)
-			t.Render(opts.Streams.Out, opts.OutputFormat)
-			return nil
+			return rakkess.PrintResources(opts, res)
+		}
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("interrupted before the access rights could be compared")
 		}
 
 		orig := res
@@ -124,14 +243,19 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("with modified flags: %v", err)
 		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("interrupted before the access rights could be compared")
+		}
 
-		t := diff.Diff(orig, mod, opts.Verbs)
-		t.Render(opts.Streams.Out, opts.OutputFormat)
-		return nil
+		return rakkess.PrintTable(opts, diff.Diff(orig, mod, opts.Verbs))
 	},
 	PostRun: func(cmd *cobra.Command, args []string) {
+		hints := opts.Streams.Out
+		if !printer.IsHumanReadable(opts.OutputFormat) || opts.Stream {
+			hints = opts.Streams.ErrOut
+		}
 		if n := opts.ConfigFlags.Namespace; n == nil || *n == "" {
-			fmt.Fprintf(opts.Streams.Out, "No namespace given, this implies cluster scope (try -n if this is not intended)\n")
+			fmt.Fprintf(hints, "No namespace given, this implies cluster scope (try -n if this is not intended)\n")
 		}
 	},
 }
@@ -148,6 +272,17 @@ func init() {
 	rootCmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
 
 	AddRakkessFlags(rootCmd)
+	rootCmd.Flags().BoolVar(&opts.Stream, constants.FlagStream, false, "print the result of every resource as JSON lines as soon as it is available")
+	rootCmd.Flags().BoolVar(&opts.ShowReasons, constants.FlagShowReasons, false, "explain the decision of the authorizer for each cell in footnotes, e.g. which RBAC binding allowed the access")
+	rootCmd.Flags().BoolVar(&opts.Summary, constants.FlagSummary, false, "print the number of allowed, denied, not applicable, and failed checks per verb after the table")
+	rootCmd.Flags().BoolVar(&opts.SummaryOnly, constants.FlagSummaryOnly, false, "like --summary, but only print the summary without the table")
+	rootCmd.Flags().IntVar(&opts.Concurrency, constants.FlagConcurrency, 32, "maximum number of resources which are checked at the same time, where zero means no limit")
+	rootCmd.Flags().Float32Var(&opts.QPS, constants.FlagQPS, 500, "maximum queries per second to the API server")
+	rootCmd.Flags().IntVar(&opts.Burst, constants.FlagBurst, 1000, "maximum burst of queries to the API server, which may exceed --qps for a short time")
+	rootCmd.Flags().DurationVar(&opts.Timeout, constants.FlagTimeout, 0, "give up on access reviews which are not finished after this time, e.g. 2m. Zero means no timeout. See --request-timeout for the timeout of single requests")
+	rootCmd.Flags().IntVar(&opts.Retries, constants.FlagRetries, 3, "retry access reviews which failed with a timeout or server error at most this many times, with exponential backoff")
+	rootCmd.Flags().StringVar(&expect, constants.FlagExpect, "", "access matrix file as printed by -o yaml or -o json. With -o junit, every verb of every resource is a test case, which fails if the access deviates from the file")
+	rootCmd.Flags().BoolVar(&opts.Subresources, constants.FlagSubresources, false, "also check subresources such as pods/exec, pods/log, or deployments/scale, each in its own row")
 	rootCmd.Flags().StringVar(&opts.AsServiceAccount, constants.FlagServiceAccount, "", "similar to --as, but impersonate as service-account. The argument must be qualified <namespace>:<sa-name> or be combined with the --namespace option. Takes precedence over --as.")
 
 	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
@@ -161,7 +296,13 @@ func init() {
 // AddRakkessFlags sets up common flags for subcommands.
 func AddRakkessFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSliceVar(&opts.Verbs, constants.FlagVerbs, []string{"list", "create", "update", "delete"}, fmt.Sprintf("show access for verbs out of (%s)", strings.Join(constants.ValidVerbs, ", ")))
-	cmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join(constants.ValidOutputFormats, ", ")))
+	cmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s), or a template as (%s)=<template>", strings.Join(constants.ValidOutputFormats, ", "), strings.Join(constants.ValidTemplateFormats, "|")))
+	cmd.Flags().BoolVar(&opts.Transpose, constants.FlagTranspose, false, "swap the axes of the table, so that verbs are shown as rows")
+	cmd.Flags().BoolVar(&opts.RotateHeaders, constants.FlagRotateHeaders, false, "print the column headers vertically to save horizontal space, most useful with --transpose")
+	cmd.Flags().StringVar(&opts.SortBy, constants.FlagSortBy, "", fmt.Sprintf("sort the rows by one of (%s)", strings.Join(constants.ValidSortKeys, ", ")))
+	cmd.Flags().StringVar(&opts.GroupBy, constants.FlagGroupBy, "", fmt.Sprintf("group the rows by one of (%s), where custom resources are collapsed into a single group", strings.Join(constants.ValidGroupings, ", ")))
+	cmd.Flags().StringVar(&opts.Color, constants.FlagColor, "auto", fmt.Sprintf("colorize the table, one of (%s). In auto mode, NO_COLOR and FORCE_COLOR are honored", strings.Join(constants.ValidColorModes, ", ")))
+	cmd.Flags().StringSliceVar(&opts.Theme, constants.FlagTheme, nil, "override symbols and colors of the table as outcome=symbol[:color], where outcome is one of (allowed, denied, explicitly-denied, not-applicable, error, cancelled). For example --theme allowed=Y:green,denied=N:red.")
 	cmd.Flags().StringSliceVar(&diffWith, constants.FlagDiffWith, nil, "Show diff for modified call. For example --diff-with=namespace=kube-system.")
 
 	opts.ConfigFlags.AddFlags(cmd.Flags())
@@ -21,18 +21,33 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+
+	"k8s.io/klog/v2"
+)
+
+// exitInterrupted is the conventional exit code after SIGINT.
+const exitInterrupted = 130
+
+var (
+	// for testing
+	exit = os.Exit
 )
 
 func catchCtrlC(cancel context.CancelFunc) {
 	catchSigs(cancel, syscall.SIGINT, syscall.SIGPIPE, syscall.SIGTERM)
 }
 
+// catchSigs cancels the context on the first signal, so that partial results
+// can be printed. The second signal exits immediately.
 func catchSigs(cancel context.CancelFunc, sigs ...os.Signal) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, sigs...)
 
 	go func() {
 		<-sigChan
+		klog.Warning("Interrupted, printing partial results. Interrupt again to exit immediately.")
 		cancel()
+		<-sigChan
+		exit(exitInterrupted)
 	}()
 }
@@ -20,9 +20,12 @@ package cmd
 
 import (
 	"context"
+	"os"
 	"sync"
 	"syscall"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCatchCtrlC(t *testing.T) {
@@ -41,3 +44,18 @@ func TestCatchCtrlC(t *testing.T) {
 	syscall.Kill(syscall.Getpid(), catchedSignal)
 	group.Wait()
 }
+
+func TestCatchCtrlC_Twice(t *testing.T) {
+	defer func() { exit = os.Exit }()
+	exited := make(chan int)
+	exit = func(code int) { exited <- code }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	catchedSignal := syscall.SIGUSR1
+	catchSigs(cancel, catchedSignal)
+
+	syscall.Kill(syscall.Getpid(), catchedSignal)
+	<-ctx.Done()
+	syscall.Kill(syscall.Getpid(), catchedSignal)
+	assert.Equal(t, exitInterrupted, <-exited)
+}
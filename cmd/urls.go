@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/validation"
+	"github.com/spf13/cobra"
+)
+
+const (
+	urlsLongHelp = `
+Show an access matrix for non-resource URLs
+
+Besides resources, the API server serves non-resource URLs such as /metrics
+or /healthz. Access to them is granted by (Cluster)Roles with nonResourceURLs
+rules.
+
+Rakkess checks access for the current user to the given paths with the given
+verbs, and prints the result as a matrix. If no paths are given, well-known
+paths of the API server are checked:
+  %s
+
+More on https://github.com/corneliusweig/rakkess/blob/v0.5.0/doc/USAGE.md#usage
+`
+
+	urlsExamples = `
+  Review access to well-known non-resource URLs
+   $ rakkess urls
+
+  Review whether a monitoring service-account may scrape metrics
+   $ rakkess urls /metrics --sa monitoring:prometheus
+
+  Review access to custom paths with custom verbs
+   $ rakkess urls /logs/ /openapi/v3 --verbs get,head
+
+  Review which bindings grant access to non-resource URLs
+   $ rakkess urls --show-reasons
+`
+)
+
+// urlsCmd represents the urls command
+var urlsCmd = &cobra.Command{
+	Use:     "urls [path...]",
+	Aliases: []string{"non-resource-urls"},
+	Short:   "Show an access matrix for non-resource URLs",
+	Long:    constants.HelpTextMapName(fmt.Sprintf(urlsLongHelp, strings.Join(constants.DefaultNonResourceURLs, ", "))),
+	Example: constants.HelpTextMapName(urlsExamples),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if !cmd.Flags().Changed(constants.FlagVerbs) {
+			opts.Verbs = constants.DefaultNonResourceVerbs
+		}
+		opts.ExpandNonResourceVerbs()
+	},
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return opts.ExpandServiceAccount()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diffWith != nil {
+			return fmt.Errorf("--%s is not supported for non-resource URLs", constants.FlagDiffWith)
+		}
+		if err := validation.RateLimits(opts); err != nil {
+			return err
+		}
+		if err := validation.Retries(opts); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		catchCtrlC(cancel)
+
+		na, err := rakkess.NonResourceURLs(ctx, opts, args)
+		if err != nil {
+			return err
+		}
+		return rakkess.PrintNonResourceURLs(opts, na)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(urlsCmd)
+
+	AddRakkessFlags(urlsCmd)
+	verbs := urlsCmd.Flags().Lookup(constants.FlagVerbs)
+	verbs.DefValue = fmt.Sprintf("[%s]", strings.Join(constants.DefaultNonResourceVerbs, ","))
+	verbs.Usage = fmt.Sprintf("show access for verbs out of (%s)", strings.Join(constants.ValidNonResourceVerbs, ", "))
+
+	urlsCmd.Flags().BoolVar(&opts.ShowReasons, constants.FlagShowReasons, false, "explain the decision of the authorizer for each cell in footnotes, e.g. which RBAC binding allowed the access")
+	urlsCmd.Flags().Float32Var(&opts.QPS, constants.FlagQPS, 500, "maximum queries per second to the API server")
+	urlsCmd.Flags().IntVar(&opts.Burst, constants.FlagBurst, 1000, "maximum burst of queries to the API server, which may exceed --qps for a short time")
+	urlsCmd.Flags().DurationVar(&opts.Timeout, constants.FlagTimeout, 0, "give up on access reviews which are not finished after this time, e.g. 2m. Zero means no timeout. See --request-timeout for the timeout of single requests")
+	urlsCmd.Flags().IntVar(&opts.Retries, constants.FlagRetries, 3, "retry access reviews which failed with a timeout or server error at most this many times, with exponential backoff")
+	urlsCmd.Flags().StringVar(&opts.AsServiceAccount, constants.FlagServiceAccount, "", "similar to --as, but impersonate as service-account. The argument must be qualified <namespace>:<sa-name> or be combined with the --namespace option. Takes precedence over --as.")
+}
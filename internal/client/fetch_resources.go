@@ -18,11 +18,13 @@ package client
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/corneliusweig/rakkess/internal/options"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
 	"k8s.io/klog/v2"
 )
@@ -32,18 +34,35 @@ var (
 	getDiscoveryClient = getDiscoveryClientImpl
 )
 
-// GroupResource contains the APIGroup and APIResource
+// GroupResource contains the APIGroup, the preferred version, and APIResource.
+// The name of the APIResource of a subresource contains a slash, e.g. 'pods/exec'.
 type GroupResource struct {
 	APIGroup    string
+	APIVersion  string
 	APIResource metav1.APIResource
 }
 
-// Extracts the full name including APIGroup, e.g. 'deployment.apps'
+// Extracts the full name including APIGroup, e.g. 'deployment.apps' or
+// 'deployments.apps/scale'
 func (g GroupResource) fullName() string {
-	if g.APIGroup == "" {
-		return g.APIResource.Name
+	resource, subresource := g.split()
+	if g.APIGroup != "" {
+		resource = fmt.Sprintf("%s.%s", resource, g.APIGroup)
 	}
-	return fmt.Sprintf("%s.%s", g.APIResource.Name, g.APIGroup)
+	if subresource != "" {
+		resource = fmt.Sprintf("%s/%s", resource, subresource)
+	}
+	return resource
+}
+
+// split separates the resource name from the subresource name, which is
+// empty if this is not a subresource.
+func (g GroupResource) split() (string, string) {
+	parts := strings.SplitN(g.APIResource.Name, "/", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
 }
 
 // FetchAvailableGroupResources fetches a list of known APIResources on the server.
@@ -80,13 +99,18 @@ func FetchAvailableGroupResources(opts *options.RakkessOptions) ([]GroupResource
 			klog.Warningf("Cannot parse groupVersion: %s", err)
 			continue
 		}
-		for _, r := range list.APIResources {
+		apiResources := list.APIResources
+		if opts.Subresources {
+			apiResources = append(apiResources, fetchSubresources(client, list)...)
+		}
+		for _, r := range apiResources {
 			if len(r.Verbs) == 0 {
 				continue
 			}
 
 			grs = append(grs, GroupResource{
 				APIGroup:    gv.Group,
+				APIVersion:  gv.Version,
 				APIResource: r,
 			})
 		}
@@ -95,6 +119,31 @@ func FetchAvailableGroupResources(opts *options.RakkessOptions) ([]GroupResource
 	return grs, nil
 }
 
+// fetchSubresources fetches the subresources of the resources in the given
+// list, e.g. 'pods/exec' for 'pods'. The preferred resources do not include
+// subresources, so they need to be looked up per group version.
+func fetchSubresources(client discovery.DiscoveryInterface, list *metav1.APIResourceList) []metav1.APIResource {
+	full, err := client.ServerResourcesForGroupVersion(list.GroupVersion)
+	if err != nil {
+		klog.Warningf("Could not fetch subresources of %s, result will be incomplete: %s", list.GroupVersion, err)
+		return nil
+	}
+
+	parents := sets.NewString()
+	for _, r := range list.APIResources {
+		parents.Insert(r.Name)
+	}
+
+	var subresources []metav1.APIResource
+	for _, r := range full.APIResources {
+		parts := strings.SplitN(r.Name, "/", 2)
+		if len(parts) == 2 && parents.Has(parts[0]) {
+			subresources = append(subresources, r)
+		}
+	}
+	return subresources
+}
+
 func getDiscoveryClientImpl(opts *options.RakkessOptions) (discovery.CachedDiscoveryInterface, error) {
 	return opts.DiscoveryClient()
 }
@@ -33,6 +33,7 @@ import (
 type fakeCachedDiscoveryInterface struct {
 	invalidateCalls int
 	next            metav1.APIResourceList
+	full            *metav1.APIResourceList
 	err             error
 	fresh           bool
 }
@@ -61,7 +62,10 @@ func (c *fakeCachedDiscoveryInterface) ServerGroupsAndResources() ([]*metav1.API
 }
 
 func (c *fakeCachedDiscoveryInterface) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
-	panic("not implemented")
+	if c.full == nil || c.full.GroupVersion != groupVersion {
+		return nil, fmt.Errorf("unknown group version %s", groupVersion)
+	}
+	return c.full, nil
 }
 
 func (c *fakeCachedDiscoveryInterface) ServerResources() ([]*metav1.APIResourceList, error) {
@@ -109,16 +113,30 @@ var (
 		Namespaced: true,
 		Verbs:      []string{"list"},
 	}
+	bBarExec = metav1.APIResource{
+		Name:       "bar/exec",
+		Kind:       "BarExecOptions",
+		Namespaced: true,
+		Verbs:      []string{"create", "get"},
+	}
+	bOtherStatus = metav1.APIResource{
+		Name:       "other/status",
+		Kind:       "Other",
+		Namespaced: true,
+		Verbs:      []string{"get"},
+	}
 )
 
 func TestFetchAvailableGroupResources(t *testing.T) {
 	tests := []struct {
-		name      string
-		namespace string
-		verbs     []string
-		resources metav1.APIResourceList
-		err       error
-		expected  interface{}
+		name         string
+		namespace    string
+		verbs        []string
+		subresources bool
+		resources    metav1.APIResourceList
+		full         *metav1.APIResourceList
+		err          error
+		expected     interface{}
 	}{
 		{
 			name:  "cluster resources",
@@ -127,7 +145,7 @@ func TestFetchAvailableGroupResources(t *testing.T) {
 				GroupVersion: "a/v1",
 				APIResources: []metav1.APIResource{aFoo, aNoVerbs},
 			},
-			expected: []GroupResource{{APIGroup: "a", APIResource: aFoo}},
+			expected: []GroupResource{{APIGroup: "a", APIVersion: "v1", APIResource: aFoo}},
 		},
 		{
 			name:      "namespaced resources",
@@ -137,7 +155,7 @@ func TestFetchAvailableGroupResources(t *testing.T) {
 				GroupVersion: "b/v1",
 				APIResources: []metav1.APIResource{bBar},
 			},
-			expected: []GroupResource{{APIGroup: "b", APIResource: bBar}},
+			expected: []GroupResource{{APIGroup: "b", APIVersion: "v1", APIResource: bBar}},
 		},
 		{
 			name:  "incomplete cluster resources",
@@ -147,7 +165,7 @@ func TestFetchAvailableGroupResources(t *testing.T) {
 				GroupVersion: "a/v1",
 				APIResources: []metav1.APIResource{aFoo, aNoVerbs},
 			},
-			expected: []GroupResource{{APIGroup: "a", APIResource: aFoo}},
+			expected: []GroupResource{{APIGroup: "a", APIVersion: "v1", APIResource: aFoo}},
 		},
 		{
 			name:      "incomplete namespaced resources",
@@ -158,7 +176,36 @@ func TestFetchAvailableGroupResources(t *testing.T) {
 				GroupVersion: "b/v1",
 				APIResources: []metav1.APIResource{bBar},
 			},
-			expected: []GroupResource{{APIGroup: "b", APIResource: bBar}},
+			expected: []GroupResource{{APIGroup: "b", APIVersion: "v1", APIResource: bBar}},
+		},
+		{
+			name:         "subresources",
+			namespace:    "any-namespace",
+			verbs:        []string{"list"},
+			subresources: true,
+			resources: metav1.APIResourceList{
+				GroupVersion: "b/v1",
+				APIResources: []metav1.APIResource{bBar},
+			},
+			full: &metav1.APIResourceList{
+				GroupVersion: "b/v1",
+				APIResources: []metav1.APIResource{bBar, bBarExec, bOtherStatus},
+			},
+			expected: []GroupResource{
+				{APIGroup: "b", APIVersion: "v1", APIResource: bBar},
+				{APIGroup: "b", APIVersion: "v1", APIResource: bBarExec},
+			},
+		},
+		{
+			name:         "subresources cannot be fetched",
+			namespace:    "any-namespace",
+			verbs:        []string{"list"},
+			subresources: true,
+			resources: metav1.APIResourceList{
+				GroupVersion: "b/v1",
+				APIResources: []metav1.APIResource{bBar},
+			},
+			expected: []GroupResource{{APIGroup: "b", APIVersion: "v1", APIResource: bBar}},
 		},
 		{
 			name:      "empty api-resources",
@@ -176,6 +223,7 @@ func TestFetchAvailableGroupResources(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			fakeRbacClient := &fakeCachedDiscoveryInterface{
 				next: test.resources,
+				full: test.full,
 				err:  test.err,
 			}
 
@@ -188,6 +236,7 @@ func TestFetchAvailableGroupResources(t *testing.T) {
 				ConfigFlags: &genericclioptions.ConfigFlags{
 					Namespace: &test.namespace,
 				},
+				Subresources: test.subresources,
 			}
 			grs, err := FetchAvailableGroupResources(opts)
 			assert.NoError(t, err)
@@ -212,4 +261,12 @@ func TestGroupResource_fullName(t *testing.T) {
 		},
 	}
 	assert.Equal(t, "foo.v1", grGroup.fullName())
+
+	grSubresource := &GroupResource{
+		APIGroup: "apps",
+		APIResource: metav1.APIResource{
+			Name: "deployments/scale",
+		},
+	}
+	assert.Equal(t, "deployments.apps/scale", grSubresource.fullName())
 }
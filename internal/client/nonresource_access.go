@@ -0,0 +1,72 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	v1 "k8s.io/api/authorization/v1"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/klog/v2"
+)
+
+// CheckNonResourceAccess determines the access rights for the given non-resource URLs
+// and verbs. There are only a few paths, so that they are checked one after another.
+// Transient failures of access reviews are retried at most retries times.
+// When the context is done, no further paths are checked.
+func CheckNonResourceAccess(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, paths, verbs []string, retries int) result.NonResourceAccess {
+	res := make(result.NonResourceAccess)
+	t := &throttle{}
+
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+		klog.V(2).Infof("Checking access for %s", path)
+
+		access := make(map[string]result.Access)
+		decisions := make(map[string]result.Decision)
+		for _, v := range verbs {
+			req := v1.SelfSubjectAccessReview{
+				Spec: v1.SelfSubjectAccessReviewSpec{
+					NonResourceAttributes: &v1.NonResourceAttributes{
+						Path: path,
+						Verb: v,
+					},
+				},
+			}
+
+			resp, err := review(ctx, sar, t, &req, retries)
+			a, d := decide(ctx, resp, err)
+			access[v] = a
+			if d != nil {
+				decisions[v] = *d
+			}
+		}
+
+		if len(decisions) == 0 {
+			decisions = nil
+		}
+		res[path] = result.NonResource{
+			Path:      path,
+			Access:    access,
+			Decisions: decisions,
+		}
+	}
+	return res
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
+	authTesting "k8s.io/client-go/testing"
+)
+
+func TestCheckNonResourceAccess(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			assert.Nil(t, sar.Spec.ResourceAttributes)
+			attrs := sar.Spec.NonResourceAttributes
+			if attrs.Path == "/metrics" && attrs.Verb == "get" {
+				sar.Status.Allowed = true
+				sar.Status.Reason = `RBAC: allowed by ClusterRoleBinding "prometheus"`
+			}
+			return true, sar, nil
+		})
+
+	results := CheckNonResourceAccess(context.Background(), fakeReviews, []string{"/metrics", "/healthz"}, []string{"get", "post"}, 0)
+
+	assert.Equal(t, result.NonResourceAccess{
+		"/metrics": {
+			Path:      "/metrics",
+			Access:    map[string]result.Access{"get": result.Allowed, "post": result.Denied},
+			Decisions: map[string]result.Decision{"get": {Reason: `RBAC: allowed by ClusterRoleBinding "prometheus"`}},
+		},
+		"/healthz": {
+			Path:   "/healthz",
+			Access: map[string]result.Access{"get": result.Denied, "post": result.Denied},
+		},
+	}, results)
+}
+
+func TestCheckNonResourceAccess_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			cancel()
+			return true, nil, ctx.Err()
+		})
+
+	results := CheckNonResourceAccess(ctx, fakeReviews, []string{"/metrics", "/healthz"}, []string{"get"}, 3)
+
+	assert.Equal(t, result.NonResourceAccess{
+		"/metrics": {
+			Path:   "/metrics",
+			Access: map[string]result.Access{"get": result.Cancelled},
+		},
+	}, results)
+}
@@ -18,6 +18,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"github.com/corneliusweig/rakkess/internal/client/result"
@@ -29,9 +30,15 @@ import (
 )
 
 // CheckResourceAccess determines the access rights for the given GroupResources and verbs.
-// Since it needs to do a lot of requests, the SelfSubjectAccessReviewInterface needs to
-// be configured for high queries per second.
-func CheckResourceAccess(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, grs []GroupResource, verbs []string, namespace *string) result.ResourceAccess {
+// At most concurrency resources are checked at the same time, where zero means no limit.
+// When the API server rejects requests because it is overloaded, all workers slow down.
+// Transient failures of access reviews are retried at most retries times.
+// If onResult is not nil, it is called with the result of every resource as soon as it
+// is available. The calls never happen concurrently.
+// When the context is done, no further resources are checked, so that the result only
+// contains the resources which were checked or in flight at that time. The verbs of
+// resources in flight are marked as cancelled, unless the context hit its deadline.
+func CheckResourceAccess(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, grs []GroupResource, verbs []string, namespace *string, concurrency, retries int, onResult func(result.Resource)) result.ResourceAccess {
 	var mu sync.Mutex // guards res
 	res := make(result.ResourceAccess)
 
@@ -40,61 +47,171 @@ func CheckResourceAccess(ctx context.Context, sar authv1.SelfSubjectAccessReview
 		ns = *namespace
 	}
 
+	if concurrency < 1 || concurrency > len(grs) {
+		concurrency = len(grs)
+	}
+
+	jobs := make(chan GroupResource)
+	t := &throttle{}
+
 	var wg sync.WaitGroup
-	for _, gr := range grs {
+	for i := 0; i < concurrency; i++ {
 		wg.Add(1)
-		// copy captured variables
-		namespace := ns
-		gr := gr
 		go func() {
 			defer wg.Done()
-
-			klog.V(2).Infof("Checking access for %s", gr.fullName())
-
-			// This seems to be a bug in kubernetes. If namespace is set for non-namespaced
-			// resources, the access is reported as "allowed", but in fact it is forbidden.
-			if !gr.APIResource.Namespaced {
-				namespace = ""
-			}
-
-			allowedVerbs := sets.NewString(gr.APIResource.Verbs...)
-
-			access := make(map[string]result.Access)
-			for _, v := range verbs {
-				if !allowedVerbs.Has(v) {
-					access[v] = result.NotApplicable
+			for gr := range jobs {
+				if ctx.Err() != nil {
+					// not yet in flight
 					continue
 				}
+				r := checkResource(ctx, sar, t, gr, verbs, ns, retries)
 
-				req := v1.SelfSubjectAccessReview{
-					Spec: v1.SelfSubjectAccessReviewSpec{
-						ResourceAttributes: &v1.ResourceAttributes{
-							Verb:      v,
-							Resource:  gr.APIResource.Name,
-							Group:     gr.APIGroup,
-							Namespace: namespace,
-						},
-					},
+				mu.Lock()
+				res[gr.fullName()] = r
+				if onResult != nil {
+					onResult(r)
 				}
-
-				var a result.Access
-				resp, err := sar.Create(ctx, &req, metav1.CreateOptions{})
-				switch {
-				case err != nil:
-					a = result.RequestErr
-				case resp.Status.Allowed:
-					a = result.Allowed
-				}
-				access[v] = a
+				mu.Unlock()
 			}
-
-			mu.Lock()
-			res[gr.fullName()] = access
-			mu.Unlock()
 		}()
 	}
 
+	for _, gr := range grs {
+		if ctx.Err() != nil {
+			// stop scheduling, but let the workers finish the resources in flight
+			break
+		}
+		jobs <- gr
+	}
+	close(jobs)
 	wg.Wait()
 
 	return res
 }
+
+// checkResource determines the access rights for a single GroupResource.
+func checkResource(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, t *throttle, gr GroupResource, verbs []string, namespace string, retries int) result.Resource {
+	klog.V(2).Infof("Checking access for %s", gr.fullName())
+
+	// This seems to be a bug in kubernetes. If namespace is set for non-namespaced
+	// resources, the access is reported as "allowed", but in fact it is forbidden.
+	if !gr.APIResource.Namespaced {
+		namespace = ""
+	}
+
+	allowedVerbs := sets.NewString(gr.APIResource.Verbs...)
+	resource, subresource := gr.split()
+
+	access := make(map[string]result.Access)
+	decisions := make(map[string]result.Decision)
+	for _, v := range verbs {
+		if !allowedVerbs.Has(v) {
+			access[v] = result.NotApplicable
+			continue
+		}
+
+		req := v1.SelfSubjectAccessReview{
+			Spec: v1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &v1.ResourceAttributes{
+					Verb:        v,
+					Resource:    resource,
+					Subresource: subresource,
+					Group:       gr.APIGroup,
+					Namespace:   namespace,
+				},
+			},
+		}
+
+		resp, err := review(ctx, sar, t, &req, retries)
+		a, d := decide(ctx, resp, err)
+		access[v] = a
+		if d != nil {
+			decisions[v] = *d
+		}
+	}
+
+	if len(decisions) == 0 {
+		decisions = nil
+	}
+
+	return result.Resource{
+		Name:       gr.APIResource.Name,
+		Group:      gr.APIGroup,
+		Version:    gr.APIVersion,
+		Kind:       gr.APIResource.Kind,
+		Namespaced: gr.APIResource.Namespaced,
+		ShortNames: gr.APIResource.ShortNames,
+		Namespace:  namespace,
+		Access:     access,
+		Decisions:  decisions,
+	}
+}
+
+// decide converts the outcome of an access review into the access state. The
+// decision is nil, if there are no details besides the access state.
+func decide(ctx context.Context, resp *v1.SelfSubjectAccessReview, err error) (result.Access, *result.Decision) {
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.Canceled):
+		return result.Cancelled, nil
+	case err != nil:
+		return result.RequestErr, &result.Decision{
+			ErrorClass: classify(err),
+			Error:      err.Error(),
+		}
+	}
+
+	var a result.Access
+	switch {
+	case resp.Status.Allowed:
+		a = result.Allowed
+	case resp.Status.Denied:
+		a = result.ExplicitlyDenied
+	}
+	if resp.Status.Reason == "" && resp.Status.EvaluationError == "" && !resp.Status.Denied {
+		return a, nil
+	}
+	return a, &result.Decision{
+		Reason:          resp.Status.Reason,
+		EvaluationError: resp.Status.EvaluationError,
+		Denied:          resp.Status.Denied,
+	}
+}
+
+// review creates the SelfSubjectAccessReview. It is repeated after a delay,
+// if the API server is overloaded. Transient failures are retried with
+// exponential backoff, at most the given number of times.
+func review(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, t *throttle, req *v1.SelfSubjectAccessReview, retries int) (*v1.SelfSubjectAccessReview, error) {
+	delay := retryDelay
+	throttled, attempt := 0, 0
+	for {
+		if err := t.wait(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := sar.Create(ctx, req, metav1.CreateOptions{})
+		if err == nil {
+			t.relax()
+			return resp, nil
+		}
+
+		if t.backoff(err) {
+			if throttled == maxThrottleRetries {
+				return nil, err
+			}
+			throttled++
+			continue
+		}
+
+		if attempt == retries || !isTransient(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		attempt++
+		klog.V(2).Infof("Retrying access review in %s: %s", delay, err)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
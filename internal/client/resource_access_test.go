@@ -18,13 +18,17 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/corneliusweig/rakkess/internal/client/result"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apiV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
@@ -167,12 +171,12 @@ func TestCheckResourceAccess(t *testing.T) {
 					return false, nil, nil
 				})
 
-			results := CheckResourceAccess(ctx, fakeReviews, test.input, test.verbs, nil)
+			results := CheckResourceAccess(ctx, fakeReviews, test.input, test.verbs, nil, 0, 0, nil)
 
 			var got []string
-			for name, access := range results {
+			for name, r := range results {
 				var as []string
-				for verb, a := range access {
+				for verb, a := range r.Access {
 					var outcome string
 					switch a {
 					case result.Allowed:
@@ -192,3 +196,193 @@ func TestCheckResourceAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckResourceAccess_Resource(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, action.(authTesting.CreateAction).GetObject(), nil
+		})
+
+	namespaced := toGroupResource("apps", "deployments", "list")
+	namespaced.APIVersion = "v1"
+	namespaced.APIResource.Kind = "Deployment"
+	namespaced.APIResource.Namespaced = true
+	namespaced.APIResource.ShortNames = []string{"deploy"}
+	input := []GroupResource{namespaced, toGroupResource("", "nodes", "list")}
+	namespace := "some-ns"
+
+	results := CheckResourceAccess(context.Background(), fakeReviews, input, []string{"list"}, &namespace, 0, 0, nil)
+
+	assert.Equal(t, result.ResourceAccess{
+		"deployments.apps": {
+			Name:       "deployments",
+			Group:      "apps",
+			Version:    "v1",
+			Kind:       "Deployment",
+			Namespaced: true,
+			ShortNames: []string{"deploy"},
+			Namespace:  "some-ns",
+			Access:     map[string]result.Access{"list": result.Denied},
+		},
+		"nodes": {
+			Name:   "nodes",
+			Access: map[string]result.Access{"list": result.Denied},
+		},
+	}, results)
+}
+
+func TestCheckResourceAccess_Subresource(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			attributes := sar.Spec.ResourceAttributes
+			sar.Status.Allowed = attributes.Resource == "pods" && attributes.Subresource == "log"
+			return true, sar, nil
+		})
+
+	input := []GroupResource{toGroupResource("", "pods/log", "get"), toGroupResource("", "pods/exec", "get")}
+
+	results := CheckResourceAccess(context.Background(), fakeReviews, input, []string{"get"}, nil, 0, 0, nil)
+
+	assert.Equal(t, result.ResourceAccess{
+		"pods/log": {
+			Name:   "pods/log",
+			Access: map[string]result.Access{"get": result.Allowed},
+		},
+		"pods/exec": {
+			Name:   "pods/exec",
+			Access: map[string]result.Access{"get": result.Denied},
+		},
+	}, results)
+}
+
+func TestCheckResourceAccess_Decisions(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			if sar.Spec.ResourceAttributes.Verb == "list" {
+				sar.Status.Allowed = true
+				sar.Status.Reason = `RBAC: allowed by ClusterRoleBinding "view"`
+			} else {
+				sar.Status.Denied = true
+				sar.Status.EvaluationError = "webhook unavailable"
+			}
+			return true, sar, nil
+		})
+
+	input := []GroupResource{toGroupResource("", "pods", "list", "delete")}
+
+	results := CheckResourceAccess(context.Background(), fakeReviews, input, []string{"list", "delete"}, nil, 0, 0, nil)
+
+	assert.Equal(t, map[string]result.Access{
+		"list":   result.Allowed,
+		"delete": result.ExplicitlyDenied,
+	}, results["pods"].Access)
+	assert.Equal(t, map[string]result.Decision{
+		"list":   {Reason: `RBAC: allowed by ClusterRoleBinding "view"`},
+		"delete": {EvaluationError: "webhook unavailable", Denied: true},
+	}, results["pods"].Decisions)
+}
+
+func TestCheckResourceAccess_OnResult(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, action.(authTesting.CreateAction).GetObject(), nil
+		})
+
+	input := []GroupResource{toGroupResource("", "pods", "list"), toGroupResource("apps", "deployments", "list")}
+
+	var streamed []string
+	results := CheckResourceAccess(context.Background(), fakeReviews, input, []string{"list"}, nil, 0, 0, func(r result.Resource) {
+		streamed = append(streamed, r.Name)
+	})
+
+	sort.Strings(streamed)
+	assert.Equal(t, []string{"deployments", "pods"}, streamed)
+	assert.Len(t, results, 2)
+}
+
+func TestCheckResourceAccess_Concurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return true, action.(authTesting.CreateAction).GetObject(), nil
+		})
+
+	var input []GroupResource
+	for i := 0; i < 10; i++ {
+		input = append(input, toGroupResource("", fmt.Sprintf("resource%d", i), "list"))
+	}
+
+	results := CheckResourceAccess(context.Background(), fakeReviews, input, []string{"list"}, nil, 2, 0, nil)
+
+	assert.Len(t, results, 10)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+}
+
+func TestCheckResourceAccess_TooManyRequests(t *testing.T) {
+	var calls int32
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			if atomic.AddInt32(&calls, 1) <= 2 {
+				return true, nil, apierrors.NewTooManyRequests("slow down", 0)
+			}
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			sar.Status.Allowed = true
+			return true, sar, nil
+		})
+
+	input := []GroupResource{toGroupResource("", "pods", "list")}
+
+	results := CheckResourceAccess(context.Background(), fakeReviews, input, []string{"list"}, nil, 1, 0, nil)
+
+	assert.Equal(t, map[string]result.Access{"list": result.Allowed}, results["pods"].Access)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestCheckResourceAccess_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			if sar.Spec.ResourceAttributes.Verb == "list" {
+				sar.Status.Allowed = true
+				return true, sar, nil
+			}
+			cancel()
+			return true, nil, ctx.Err()
+		})
+
+	input := []GroupResource{
+		toGroupResource("", "pods", "list", "delete", "get"),
+		toGroupResource("", "secrets", "list"),
+		toGroupResource("", "services", "list"),
+	}
+
+	results := CheckResourceAccess(ctx, fakeReviews, input, []string{"list", "delete", "get"}, nil, 1, 3, nil)
+
+	assert.Equal(t, result.ResourceAccess{
+		"pods": {
+			Name:   "pods",
+			Access: map[string]result.Access{"list": result.Allowed, "delete": result.Cancelled, "get": result.Cancelled},
+		},
+	}, results)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// cellKey identifies the cell of a resource and verb.
+type cellKey struct {
+	resource, group, verb string
+}
+
+// ReadMatrix reads an access matrix from a file, as printed with '-o json' or
+// '-o yaml'.
+func ReadMatrix(path string) (*AccessMatrix, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read expected access matrix")
+	}
+	m := &AccessMatrix{}
+	if err := yaml.UnmarshalStrict(data, m); err != nil {
+		return nil, errors.Wrapf(err, "parse expected access matrix %s", path)
+	}
+	if m.APIVersion != MatrixAPIVersion || m.Kind != MatrixKind {
+		return nil, fmt.Errorf("%s is not an access matrix, expected apiVersion %s and kind %s", path, MatrixAPIVersion, MatrixKind)
+	}
+	return m, nil
+}
+
+// ExpectTable is like Table, but records a failure for every entry which
+// deviates from the expected access matrix. Like in a diff, only a change of
+// the allowed state is a deviation. Entries whose access review failed or was
+// cancelled on either side are not compared, and entries which are missing from the expected
+// matrix only deviate if access is allowed.
+func (ra ResourceAccess) ExpectTable(verbs []string, expected *AccessMatrix) *printer.Table {
+	states := make(map[cellKey]string, len(expected.Status.Cells))
+	for _, c := range expected.Status.Cells {
+		states[cellKey{c.Resource, c.Group, c.Verb}] = c.State
+	}
+
+	t := ra.Table(verbs)
+	for i, name := range ra.sortedNames() {
+		r := ra[name]
+		var failures []string
+		for _, v := range verbs {
+			failures = append(failures, deviation(r.Access[v], states[cellKey{r.Name, r.Group, v}]))
+		}
+		t.Rows[i].Failures = failures
+	}
+	return t
+}
+
+// deviation explains how the access deviates from the expected state. It is
+// empty if the access is as expected or either side is unknown.
+func deviation(a Access, expected string) string {
+	if a == RequestErr || a == Cancelled {
+		return ""
+	}
+	switch expected {
+	case "":
+		if a == Allowed {
+			return "allowed, but missing from the expected access matrix"
+		}
+		return ""
+	case RequestErr.String(), Cancelled.String():
+		return ""
+	}
+	if (a == Allowed) == (expected == Allowed.String()) {
+		return ""
+	}
+	return fmt.Sprintf("expected %s, but %s", expected, a)
+}
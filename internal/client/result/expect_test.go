@@ -0,0 +1,68 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestReadMatrix(t *testing.T) {
+	dir := t.TempDir()
+	m := testResources.Matrix([]string{"list", "create"})
+	data, err := yaml.Marshal(m)
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "expected.yaml")
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+
+	got, err := ReadMatrix(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, m.Status.Cells, got.Status.Cells)
+}
+
+func TestReadMatrix_WrongKind(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "role.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("apiVersion: rbac.authorization.k8s.io/v1\nkind: Role\n"), 0o600))
+
+	_, err := ReadMatrix(path)
+
+	assert.EqualError(t, err, path+" is not an access matrix, expected apiVersion "+MatrixAPIVersion+" and kind "+MatrixKind)
+}
+
+func TestResourceAccess_ExpectTable(t *testing.T) {
+	ra := ResourceAccess{
+		"configmaps":       {Name: "configmaps", Access: map[string]Access{"get": Allowed, "list": Denied, "create": RequestErr}},
+		"deployments.apps": {Name: "deployments", Group: "apps", Access: map[string]Access{"get": Allowed, "list": ExplicitlyDenied, "create": Denied}},
+	}
+	expected := &AccessMatrix{Status: MatrixStatus{Cells: []Cell{
+		{Resource: "configmaps", Verb: "get", State: "allowed"},
+		{Resource: "configmaps", Verb: "list", State: "allowed"},
+		{Resource: "configmaps", Verb: "create", State: "denied"},
+		{Resource: "deployments", Group: "apps", Verb: "list", State: "denied"},
+		{Resource: "deployments", Group: "apps", Verb: "create", State: "error"},
+	}}}
+
+	tab := ra.ExpectTable([]string{"get", "list", "create"}, expected)
+
+	assert.Equal(t, []string{"", "expected allowed, but denied", ""}, tab.Rows[0].Failures)
+	assert.Equal(t, []string{"allowed, but missing from the expected access matrix", "", ""}, tab.Rows[1].Failures)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+)
+
+const (
+	coreSection            = "core"
+	customResourcesSection = "custom resources"
+)
+
+// GroupByAPIGroup groups the rows of the table by their API group. The core
+// group comes first, followed by the other built-in groups. The groups of
+// custom resources are collapsed into a single section at the end.
+func GroupByAPIGroup(t *printer.Table) {
+	for i := range t.Rows {
+		if !isBuiltinSection(t.Rows[i].Section) {
+			t.Rows[i].Section = customResourcesSection
+		}
+	}
+	t.GroupBySection(func(a, b string) bool {
+		if ra, rb := sectionRank(a), sectionRank(b); ra != rb {
+			return ra < rb
+		}
+		return a < b
+	})
+}
+
+// isBuiltinSection checks if the section belongs to an API group of Kubernetes.
+// Such groups either have no domain, e.g. 'apps', or are in the reserved
+// domain 'k8s.io'.
+func isBuiltinSection(section string) bool {
+	return !strings.Contains(section, ".") || strings.HasSuffix(section, ".k8s.io")
+}
+
+func sectionRank(section string) int {
+	switch section {
+	case coreSection:
+		return 0
+	case customResourcesSection:
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupByAPIGroup(t *testing.T) {
+	ra := ResourceAccess{
+		"certificates.cert-manager.io": {Name: "certificates", Group: "cert-manager.io"},
+		"configmaps":                   {Name: "configmaps"},
+		"deployments.apps":             {Name: "deployments", Group: "apps"},
+		"ingresses.networking.k8s.io":  {Name: "ingresses", Group: "networking.k8s.io"},
+		"issuers.cert-manager.io":      {Name: "issuers", Group: "cert-manager.io"},
+		"pods":                         {Name: "pods"},
+	}
+	table := ra.Table(nil)
+
+	GroupByAPIGroup(table)
+
+	var rows [][]string
+	for _, row := range table.Rows {
+		rows = append(rows, []string{row.Section, row.Intro[0]})
+	}
+	assert.Equal(t, [][]string{
+		{"core", "configmaps"},
+		{"core", "pods"},
+		{"apps", "deployments.apps"},
+		{"networking.k8s.io", "ingresses.networking.k8s.io"},
+		{"custom resources", "certificates.cert-manager.io"},
+		{"custom resources", "issuers.cert-manager.io"},
+	}, rows)
+	assert.True(t, table.Grouped)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// MatrixAPIVersion is the version of the machine-readable access matrix.
+	// It must be bumped for incompatible changes of the format.
+	MatrixAPIVersion = "rakkess.corneliusweig.github.io/v1alpha1"
+	// MatrixKind is the kind of the machine-readable access matrix.
+	MatrixKind = "AccessMatrix"
+)
+
+// AccessMatrix is the versioned, machine-readable representation of an
+// access matrix. It is shared by the resource and the subject matrix.
+type AccessMatrix struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   MatrixSpec   `json:"spec"`
+	Status MatrixStatus `json:"status"`
+}
+
+// MatrixSpec describes the query which produced the access matrix.
+type MatrixSpec struct {
+	// Context is the kubeconfig context of the cluster under review.
+	Context string `json:"context,omitempty"`
+	// User is the impersonated user, if any.
+	User string `json:"user,omitempty"`
+	// Groups are the impersonated groups, if any.
+	Groups []string `json:"groups,omitempty"`
+	// ServiceAccount is the impersonated service account, if any. It takes
+	// the form '<namespace>:<name>'.
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// Namespace is the namespace under review. It is empty for cluster scope.
+	Namespace string `json:"namespace,omitempty"`
+	// Verbs are the verbs for which access was checked.
+	Verbs []string `json:"verbs"`
+	// Resource is the resource for which subjects were reviewed. It is
+	// only set for the subject matrix.
+	Resource string `json:"resource,omitempty"`
+	// ResourceName restricts the subject matrix to a single resource instance.
+	ResourceName string `json:"resourceName,omitempty"`
+}
+
+// MatrixStatus holds the result of the access review.
+type MatrixStatus struct {
+	Cells []Cell `json:"cells"`
+}
+
+// Cell is a single entry of the access matrix. It is self-contained, so
+// that consumers can filter cells without knowing the matrix layout.
+type Cell struct {
+	// Subject is only set for the subject matrix.
+	Subject   *SubjectRef `json:"subject,omitempty"`
+	Resource  string      `json:"resource,omitempty"`
+	Group     string      `json:"group"`
+	Namespace string      `json:"namespace"`
+	// NonResourceURL is only set for the non-resource URL matrix.
+	NonResourceURL string `json:"nonResourceURL,omitempty"`
+	Verb           string `json:"verb"`
+	// State is one of 'allowed', 'denied', 'explicitly-denied',
+	// 'not-applicable', 'error', or 'cancelled'.
+	State string `json:"state"`
+	// Reason, EvaluationError, and Denied are copied from the status of the
+	// access review, if the authorizer provided them.
+	Reason          string `json:"reason,omitempty"`
+	EvaluationError string `json:"evaluationError,omitempty"`
+	Denied          bool   `json:"denied,omitempty"`
+	// ErrorClass and Error describe a failed access review. The class is one
+	// of 'forbidden', 'timeout', 'server-error', 'not-found', or 'other'.
+	ErrorClass string `json:"errorClass,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+var _ runtime.Object = &AccessMatrix{}
+
+// DeepCopyObject implements runtime.Object, so that the access matrix can be
+// passed to the kubectl printers.
+func (m *AccessMatrix) DeepCopyObject() runtime.Object {
+	if m == nil {
+		return nil
+	}
+	out := &AccessMatrix{
+		TypeMeta: m.TypeMeta,
+		Spec:     m.Spec,
+	}
+	m.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if m.Spec.Groups != nil {
+		out.Spec.Groups = append([]string{}, m.Spec.Groups...)
+	}
+	if m.Spec.Verbs != nil {
+		out.Spec.Verbs = append([]string{}, m.Spec.Verbs...)
+	}
+	if m.Status.Cells != nil {
+		out.Status.Cells = make([]Cell, len(m.Status.Cells))
+		for i, c := range m.Status.Cells {
+			if c.Subject != nil {
+				subject := *c.Subject
+				c.Subject = &subject
+			}
+			out.Status.Cells[i] = c
+		}
+	}
+	return out
+}
+
+func newAccessMatrix(verbs []string) *AccessMatrix {
+	return &AccessMatrix{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: MatrixAPIVersion,
+			Kind:       MatrixKind,
+		},
+		Spec: MatrixSpec{
+			Verbs: verbs,
+		},
+		Status: MatrixStatus{
+			Cells: []Cell{},
+		},
+	}
+}
+
+// newCell creates the cell for the given verb, which holds the access state
+// and the details of the decision.
+func newCell(verb string, a Access, d Decision) Cell {
+	return Cell{
+		Verb:            verb,
+		State:           a.String(),
+		Reason:          d.Reason,
+		EvaluationError: d.EvaluationError,
+		Denied:          d.Denied,
+		ErrorClass:      string(d.ErrorClass),
+		Error:           d.Error,
+	}
+}
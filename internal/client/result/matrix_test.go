@@ -0,0 +1,97 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestResourceAccess_Matrix(t *testing.T) {
+	m := testResources.Matrix([]string{"list", "create"})
+
+	assert.Equal(t, MatrixAPIVersion, m.APIVersion)
+	assert.Equal(t, MatrixKind, m.Kind)
+	assert.Equal(t, []string{"list", "create"}, m.Spec.Verbs)
+	assert.Equal(t, []Cell{
+		{Resource: "configmaps", Group: "", Namespace: "default", Verb: "list", State: "not-applicable"},
+		{Resource: "configmaps", Group: "", Namespace: "default", Verb: "create", State: "error"},
+		{Resource: "deployments", Group: "apps", Namespace: "default", Verb: "list", State: "allowed"},
+		{Resource: "deployments", Group: "apps", Namespace: "default", Verb: "create", State: "denied"},
+	}, m.Status.Cells)
+}
+
+func TestResourceAccess_Matrix_Decisions(t *testing.T) {
+	ra := ResourceAccess{
+		"pods": {
+			Name:      "pods",
+			Access:    map[string]Access{"list": Allowed, "delete": Denied},
+			Decisions: map[string]Decision{"list": {Reason: "allowed by binding"}, "delete": {Denied: true}},
+		},
+	}
+
+	m := ra.Matrix([]string{"list", "delete"})
+
+	assert.Equal(t, []Cell{
+		{Resource: "pods", Verb: "list", State: "allowed", Reason: "allowed by binding"},
+		{Resource: "pods", Verb: "delete", State: "denied", Denied: true},
+	}, m.Status.Cells)
+}
+
+func TestResourceAccess_Matrix_Errors(t *testing.T) {
+	ra := ResourceAccess{
+		"pods": {
+			Name:      "pods",
+			Access:    map[string]Access{"list": RequestErr},
+			Decisions: map[string]Decision{"list": {ErrorClass: ErrTimeout, Error: "context deadline exceeded"}},
+		},
+	}
+
+	m := ra.Matrix([]string{"list"})
+
+	assert.Equal(t, []Cell{
+		{Resource: "pods", Verb: "list", State: "error", ErrorClass: "timeout", Error: "context deadline exceeded"},
+	}, m.Status.Cells)
+}
+
+func TestResourceAccess_Matrix_Empty(t *testing.T) {
+	m := ResourceAccess{}.Matrix([]string{"list"})
+	assert.NotNil(t, m.Status.Cells)
+	assert.Empty(t, m.Status.Cells)
+}
+
+func TestSubjectAccess_Matrix(t *testing.T) {
+	alice := SubjectRef{Name: "alice", Kind: "User"}
+	bob := SubjectRef{Name: "bob", Kind: "ServiceAccount", Namespace: "kube-system"}
+	sa := NewSubjectAccess("deployments", "")
+	sa.Group = "apps"
+	sa.subjectToVerbs[bob] = sets.NewString("get")
+	sa.subjectToVerbs[alice] = sets.NewString("get", "list")
+	sa.subjectToVerbs[SubjectRef{Name: "carol", Kind: "User"}] = sets.NewString("watch")
+
+	m := sa.Matrix([]string{"get", "list"})
+
+	assert.Equal(t, "deployments", m.Spec.Resource)
+	assert.Equal(t, []Cell{
+		{Subject: &alice, Resource: "deployments", Group: "apps", Verb: "get", State: "allowed"},
+		{Subject: &alice, Resource: "deployments", Group: "apps", Verb: "list", State: "allowed"},
+		{Subject: &bob, Resource: "deployments", Group: "apps", Verb: "get", State: "allowed"},
+		{Subject: &bob, Resource: "deployments", Group: "apps", Verb: "list", State: "denied"},
+	}, m.Status.Cells)
+}
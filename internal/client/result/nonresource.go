@@ -0,0 +1,100 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+)
+
+// NonResourceAccess holds the access result for non-resource URLs. It is
+// keyed by the path, e.g. '/metrics'.
+type NonResourceAccess map[string]NonResource
+
+// NonResource holds the access result for a single non-resource URL.
+type NonResource struct {
+	// Path is the non-resource URL, e.g. '/metrics'.
+	Path string
+	// Access holds the access result for each verb.
+	Access map[string]Access
+	// Decisions holds the details of the authorizer's decision for each
+	// reviewed verb.
+	Decisions map[string]Decision
+}
+
+func (na NonResourceAccess) sortedPaths() []string {
+	paths := make([]string, 0, len(na))
+	for path := range na {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Table shows the access for each path and verb.
+func (na NonResourceAccess) Table(verbs []string) *printer.Table {
+	headers := []string{"URL"}
+	for _, v := range verbs {
+		headers = append(headers, strings.ToUpper(v))
+	}
+
+	p := printer.TableWithHeaders(headers)
+	p.Legend = legend
+
+	for _, path := range na.sortedPaths() {
+		var outcomes []printer.Outcome
+
+		u := na[path]
+		for _, v := range verbs {
+			outcomes = append(outcomes, u.Access[v].outcome())
+		}
+		p.AddRow([]string{path}, outcomes...)
+		p.Rows[len(p.Rows)-1].Notes = notes(u.Decisions, verbs)
+	}
+	return p
+}
+
+// Matrix converts the result into its machine-readable representation.
+func (na NonResourceAccess) Matrix(verbs []string) *AccessMatrix {
+	m := newAccessMatrix(verbs)
+	for _, path := range na.sortedPaths() {
+		u := na[path]
+		for _, v := range verbs {
+			c := newCell(v, u.Access[v], u.Decisions[v])
+			c.NonResourceURL = u.Path
+			m.Status.Cells = append(m.Status.Cells, c)
+		}
+	}
+	return m
+}
+
+// Incomplete checks if any access review for the path was cancelled.
+func (u NonResource) Incomplete() bool {
+	for _, a := range u.Access {
+		if a == Cancelled {
+			return true
+		}
+	}
+	return false
+}
+
+// TimedOut checks if any access review for the path failed with a timeout.
+func (u NonResource) TimedOut() bool {
+	return timedOut(u.Decisions)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNonResources = NonResourceAccess{
+	"/metrics": {
+		Path:      "/metrics",
+		Access:    map[string]Access{"get": Allowed, "post": Denied},
+		Decisions: map[string]Decision{"get": {Reason: "allowed by binding"}},
+	},
+	"/healthz": {
+		Path:   "/healthz",
+		Access: map[string]Access{"get": Allowed, "post": ExplicitlyDenied},
+	},
+}
+
+func TestNonResourceAccess_Table(t *testing.T) {
+	table := testNonResources.Table([]string{"get", "post"})
+
+	assert.Equal(t, []string{"URL", "GET", "POST"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"/healthz"}, Entries: []printer.Outcome{printer.Up, printer.Deny}},
+		{Intro: []string{"/metrics"}, Entries: []printer.Outcome{printer.Up, printer.Down}, Notes: []string{"allowed by binding", ""}},
+	}, table.Rows)
+}
+
+func TestNonResourceAccess_Matrix(t *testing.T) {
+	m := testNonResources.Matrix([]string{"get", "post"})
+
+	assert.Equal(t, []Cell{
+		{NonResourceURL: "/healthz", Verb: "get", State: "allowed"},
+		{NonResourceURL: "/healthz", Verb: "post", State: "explicitly-denied"},
+		{NonResourceURL: "/metrics", Verb: "get", State: "allowed", Reason: "allowed by binding"},
+		{NonResourceURL: "/metrics", Verb: "post", State: "denied"},
+	}, m.Status.Cells)
+}
+
+func TestNonResource_Incomplete(t *testing.T) {
+	assert.False(t, testNonResources["/metrics"].Incomplete())
+	assert.True(t, NonResource{Access: map[string]Access{"get": Allowed, "post": Cancelled}}.Incomplete())
+}
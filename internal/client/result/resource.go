@@ -17,51 +17,214 @@ limitations under the License.
 package result
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/corneliusweig/rakkess/internal/printer"
 )
 
-// ResourceAccess holds the access result for all resources.
-type ResourceAccess map[string]map[string]Access
+// legend explains the outcomes of an access matrix.
+var legend = map[printer.Outcome]string{
+	printer.Up:     "allowed",
+	printer.Down:   "denied",
+	printer.Deny:   "explicitly denied",
+	printer.None:   "not applicable",
+	printer.Err:    "request error",
+	printer.Cancel: "cancelled",
+}
 
-// Print implements MatrixPrinter.Print. It prints a tab-separated table with a header.
-func (ra ResourceAccess) Table(verbs []string) *printer.Table {
-	var names []string
+// ResourceAccess holds the access result for all resources. It is keyed by the
+// full resource name including the API group, e.g. 'deployments.apps'.
+type ResourceAccess map[string]Resource
+
+// Resource holds the access result for a single resource.
+type Resource struct {
+	// Name is the plural resource name, e.g. 'deployments'.
+	Name string
+	// Group is the API group of the resource, e.g. 'apps'.
+	Group string
+	// Version is the preferred version of the API group, e.g. 'v1'.
+	Version string
+	// Kind is the kind of the resource, e.g. 'Deployment'.
+	Kind string
+	// Namespaced indicates whether the resource is namespace-scoped.
+	Namespaced bool
+	// ShortNames are the abbreviations of the resource name, e.g. 'deploy'.
+	ShortNames []string
+	// Namespace is the namespace in which access was checked. It is empty
+	// for cluster-scoped resources.
+	Namespace string
+	// Access holds the access result for each verb.
+	Access map[string]Access
+	// Decisions holds the details of the authorizer's decision for each
+	// reviewed verb.
+	Decisions map[string]Decision
+}
+
+// Decision holds the details of an access review besides the access result.
+type Decision struct {
+	// Reason explains the decision, e.g. which RBAC binding allowed the access.
+	Reason string
+	// EvaluationError is set if an authorizer failed to evaluate the review.
+	EvaluationError string
+	// Denied is set if an authorizer explicitly denied the access.
+	Denied bool
+	// ErrorClass categorizes the failure, if the access review failed.
+	ErrorClass ErrorClass
+	// Error is the message of the failed access review.
+	Error string
+}
+
+// note summarizes the decision for a footnote. It is empty if the authorizer
+// gave no details.
+func (d Decision) note() string {
+	var parts []string
+	if d.Error != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", d.ErrorClass, d.Error))
+	}
+	if d.Reason != "" {
+		parts = append(parts, d.Reason)
+	}
+	if d.EvaluationError != "" {
+		parts = append(parts, "evaluation error: "+d.EvaluationError)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Section is the name of the API group for grouping related rows.
+func (r Resource) Section() string {
+	if r.Group == "" {
+		return coreSection
+	}
+	return r.Group
+}
+
+func (ra ResourceAccess) sortedNames() []string {
+	names := make([]string, 0, len(ra))
 	for name := range ra {
 		names = append(names, name)
 	}
 	sort.Strings(names)
+	return names
+}
 
+// Print implements MatrixPrinter.Print. It prints a tab-separated table with a header.
+func (ra ResourceAccess) Table(verbs []string) *printer.Table {
+	return ra.table(verbs, []string{"NAME"}, func(name string, _ Resource) []string {
+		return []string{name}
+	})
+}
+
+// SplitTable is like Table, but shows the resource name, API group, and
+// namespace in separate columns.
+func (ra ResourceAccess) SplitTable(verbs []string) *printer.Table {
+	return ra.table(verbs, []string{"NAME", "GROUP", "NAMESPACE"}, func(_ string, r Resource) []string {
+		return []string{r.Name, r.Group, r.Namespace}
+	})
+}
+
+// WideTable is like Table, but additionally shows the API group, version, kind,
+// scope, and short names of each resource.
+func (ra ResourceAccess) WideTable(verbs []string) *printer.Table {
+	intro := []string{"NAME", "APIGROUP", "VERSION", "KIND", "NAMESPACED", "SHORTNAMES"}
+	return ra.table(verbs, intro, func(_ string, r Resource) []string {
+		return []string{r.Name, r.Group, r.Version, r.Kind, strconv.FormatBool(r.Namespaced), strings.Join(r.ShortNames, ",")}
+	})
+}
+
+func (ra ResourceAccess) table(verbs, intro []string, introFor func(string, Resource) []string) *printer.Table {
 	// table header
-	headers := []string{"NAME"}
+	headers := intro
 	for _, v := range verbs {
 		headers = append(headers, strings.ToUpper(v))
 	}
 
 	p := printer.TableWithHeaders(headers)
+	p.Legend = legend
 
 	// table body
-	for _, name := range names {
+	for _, name := range ra.sortedNames() {
 		var outcomes []printer.Outcome
 
-		res := ra[name]
+		r := ra[name]
 		for _, v := range verbs {
-			var o printer.Outcome
-			switch res[v] {
-			case Denied:
-				o = printer.Down
-			case Allowed:
-				o = printer.Up
-			case NotApplicable:
-				o = printer.None
-			case RequestErr:
-				o = printer.Err
-			}
-			outcomes = append(outcomes, o)
+			outcomes = append(outcomes, r.Access[v].outcome())
 		}
-		p.AddRow([]string{name}, outcomes...)
+		p.AddRowInSection(r.Section(), introFor(name, r), outcomes...)
+		p.Rows[len(p.Rows)-1].Notes = notes(r.Decisions, verbs)
 	}
 	return p
 }
+
+// notes summarizes the decisions for each verb. It is nil if there are no
+// decisions at all.
+func notes(decisions map[string]Decision, verbs []string) []string {
+	if len(decisions) == 0 {
+		return nil
+	}
+	notes := make([]string, 0, len(verbs))
+	for _, v := range verbs {
+		notes = append(notes, decisions[v].note())
+	}
+	return notes
+}
+
+// Matrix converts the result into its machine-readable representation.
+func (ra ResourceAccess) Matrix(verbs []string) *AccessMatrix {
+	m := newAccessMatrix(verbs)
+	for _, name := range ra.sortedNames() {
+		m.Status.Cells = append(m.Status.Cells, ra[name].Cells(verbs)...)
+	}
+	return m
+}
+
+// Cells converts the result for a single resource into its machine-readable
+// representation, with one cell per verb.
+func (r Resource) Cells(verbs []string) []Cell {
+	cells := make([]Cell, 0, len(verbs))
+	for _, v := range verbs {
+		c := newCell(v, r.Access[v], r.Decisions[v])
+		c.Resource = r.Name
+		c.Group = r.Group
+		c.Namespace = r.Namespace
+		cells = append(cells, c)
+	}
+	return cells
+}
+
+// timedOut checks if any of the decisions is a timeout error.
+func timedOut(decisions map[string]Decision) bool {
+	for _, d := range decisions {
+		if d.ErrorClass == ErrTimeout {
+			return true
+		}
+	}
+	return false
+}
+
+// Incomplete checks if any access review for the resource was cancelled.
+func (r Resource) Incomplete() bool {
+	for _, a := range r.Access {
+		if a == Cancelled {
+			return true
+		}
+	}
+	return false
+}
+
+// TimedOut checks if any access review for the resource failed with a timeout.
+func (r Resource) TimedOut() bool {
+	return timedOut(r.Decisions)
+}
+
+// HasErrors checks if any access review for the resource failed.
+func (r Resource) HasErrors() bool {
+	for _, a := range r.Access {
+		if a == RequestErr {
+			return true
+		}
+	}
+	return false
+}
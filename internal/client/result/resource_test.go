@@ -0,0 +1,87 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+)
+
+var testResources = ResourceAccess{
+	"deployments.apps": {
+		Name:       "deployments",
+		Group:      "apps",
+		Version:    "v1",
+		Kind:       "Deployment",
+		Namespaced: true,
+		ShortNames: []string{"deploy"},
+		Namespace:  "default",
+		Access:     map[string]Access{"list": Allowed, "create": Denied},
+	},
+	"configmaps": {
+		Name:       "configmaps",
+		Version:    "v1",
+		Kind:       "ConfigMap",
+		Namespaced: true,
+		ShortNames: []string{"cm"},
+		Namespace:  "default",
+		Access:     map[string]Access{"list": NotApplicable, "create": RequestErr},
+	},
+}
+
+func TestResourceAccess_Table(t *testing.T) {
+	table := testResources.Table([]string{"list", "create"})
+
+	assert.Equal(t, []string{"NAME", "LIST", "CREATE"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"configmaps"}, Entries: []printer.Outcome{printer.None, printer.Err}, Section: "core"},
+		{Intro: []string{"deployments.apps"}, Entries: []printer.Outcome{printer.Up, printer.Down}, Section: "apps"},
+	}, table.Rows)
+}
+
+func TestResourceAccess_SplitTable(t *testing.T) {
+	table := testResources.SplitTable([]string{"list", "create"})
+
+	assert.Equal(t, []string{"NAME", "GROUP", "NAMESPACE", "LIST", "CREATE"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"configmaps", "", "default"}, Entries: []printer.Outcome{printer.None, printer.Err}, Section: "core"},
+		{Intro: []string{"deployments", "apps", "default"}, Entries: []printer.Outcome{printer.Up, printer.Down}, Section: "apps"},
+	}, table.Rows)
+}
+
+func TestResourceAccess_WideTable(t *testing.T) {
+	table := testResources.WideTable([]string{"list", "create"})
+
+	assert.Equal(t, []string{"NAME", "APIGROUP", "VERSION", "KIND", "NAMESPACED", "SHORTNAMES", "LIST", "CREATE"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"configmaps", "", "v1", "ConfigMap", "true", "cm"}, Entries: []printer.Outcome{printer.None, printer.Err}, Section: "core"},
+		{Intro: []string{"deployments", "apps", "v1", "Deployment", "true", "deploy"}, Entries: []printer.Outcome{printer.Up, printer.Down}, Section: "apps"},
+	}, table.Rows)
+}
+
+func TestResource_TimedOut(t *testing.T) {
+	r := Resource{
+		Access:    map[string]Access{"list": RequestErr, "get": RequestErr},
+		Decisions: map[string]Decision{"list": {ErrorClass: ErrServer}},
+	}
+	assert.False(t, r.TimedOut())
+
+	r.Decisions["get"] = Decision{ErrorClass: ErrTimeout}
+	assert.True(t, r.TimedOut())
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package result
 
+import "github.com/corneliusweig/rakkess/internal/printer"
+
 type Access uint8
 
 // This encodes the access of the given subject to the resource+verb combination.
@@ -24,4 +26,65 @@ const (
 	Allowed
 	NotApplicable
 	RequestErr
+	// ExplicitlyDenied means that an authorizer denied the access explicitly,
+	// rather than having no opinion.
+	ExplicitlyDenied
+	// Cancelled means that the access was not checked, because the review
+	// was interrupted.
+	Cancelled
 )
+
+// ErrorClass categorizes failed access reviews.
+type ErrorClass string
+
+// These are the causes of failed access reviews.
+const (
+	// ErrForbidden means that the user may not create access reviews.
+	ErrForbidden ErrorClass = "forbidden"
+	// ErrTimeout means that the access review did not finish in time.
+	ErrTimeout ErrorClass = "timeout"
+	// ErrServer means that the API server or an aggregated API failed.
+	ErrServer ErrorClass = "server-error"
+	// ErrNotFound means that the API server does not know access reviews.
+	ErrNotFound ErrorClass = "not-found"
+	// ErrOther is any other failure, e.g. a connection error.
+	ErrOther ErrorClass = "other"
+)
+
+// String returns the name of the access state as used in machine-readable output.
+func (a Access) String() string {
+	switch a {
+	case Denied:
+		return "denied"
+	case Allowed:
+		return "allowed"
+	case NotApplicable:
+		return "not-applicable"
+	case RequestErr:
+		return "error"
+	case ExplicitlyDenied:
+		return "explicitly-denied"
+	case Cancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// outcome converts the access state to its table representation.
+func (a Access) outcome() printer.Outcome {
+	switch a {
+	case Allowed:
+		return printer.Up
+	case NotApplicable:
+		return printer.None
+	case RequestErr:
+		return printer.Err
+	case ExplicitlyDenied:
+		return printer.Deny
+	case Cancelled:
+		return printer.Cancel
+	default:
+		return printer.Down
+	}
+}
@@ -34,28 +34,51 @@ type RoleRef struct {
 
 // SubjectRef uniquely identifies the subject of a RoleBinding or ClusterRoleBinding
 type SubjectRef struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// BindingRef uniquely identifies a RoleBinding or ClusterRoleBinding.
+type BindingRef struct {
 	Name, Kind, Namespace string
 }
 
+// Grant records that a subject is bound to a role by a binding.
+type Grant struct {
+	Binding BindingRef
+	Role    RoleRef
+}
+
 // SubjectAccess holds the access information of all subjects for the given resource.
 type SubjectAccess struct {
 	// Resource is the kubernetes resource of this query.
 	Resource string
 	// ResourceName is the name of the kubernetes resource instance of this query.
 	ResourceName string
+	// Group is the API group of the kubernetes resource of this query.
+	Group string
+	// Namespace is the namespace of this query. It is empty for cluster scope.
+	Namespace string
 	// roleToVerbs holds all rule data concerning this resource and is extracted from Roles and ClusterRoles.
 	roleToVerbs map[RoleRef]sets.String
+	// roleToRules holds the rules of each role which match this resource.
+	roleToRules map[RoleRef][]v1.PolicyRule
 	// subjectToVerbs holds all subject access data for this resource and is extracted from RoleBindings and ClusterRoleBindings.
 	subjectToVerbs map[SubjectRef]sets.String
+	// subjectToGrants holds the bindings through which each subject gains access.
+	subjectToGrants map[SubjectRef][]Grant
 }
 
 // NewSubjectAccess creates a new SubjectAccess with initialized fields.
 func NewSubjectAccess(resource, resourceName string) *SubjectAccess {
 	return &SubjectAccess{
-		Resource:       resource,
-		ResourceName:   resourceName,
-		roleToVerbs:    make(map[RoleRef]sets.String),
-		subjectToVerbs: make(map[SubjectRef]sets.String),
+		Resource:        resource,
+		ResourceName:    resourceName,
+		roleToVerbs:     make(map[RoleRef]sets.String),
+		roleToRules:     make(map[RoleRef][]v1.PolicyRule),
+		subjectToVerbs:  make(map[SubjectRef]sets.String),
+		subjectToGrants: make(map[SubjectRef][]Grant),
 	}
 }
 
@@ -91,6 +114,43 @@ func (sa *SubjectAccess) ResolveRoleRef(r RoleRef, subjects []v1.Subject) {
 	}
 }
 
+// ResolveBinding is like ResolveRoleRef, but also records the binding through
+// which the subjects gain access.
+func (sa *SubjectAccess) ResolveBinding(b BindingRef, r RoleRef, subjects []v1.Subject) {
+	if _, ok := sa.roleToVerbs[r]; !ok {
+		return
+	}
+	sa.ResolveRoleRef(r, subjects)
+	for _, subject := range subjects {
+		s := SubjectRef{
+			Name:      subject.Name,
+			Kind:      subject.Kind,
+			Namespace: subject.Namespace,
+		}
+		sa.subjectToGrants[s] = append(sa.subjectToGrants[s], Grant{Binding: b, Role: r})
+	}
+}
+
+// Grants returns the bindings through which the given subject gains access.
+func (sa *SubjectAccess) Grants(s SubjectRef) []Grant {
+	return sa.subjectToGrants[s]
+}
+
+// RoleVerbs returns the verbs which the given role allows for the resource.
+func (sa *SubjectAccess) RoleVerbs(r RoleRef) sets.String {
+	return sa.roleToVerbs[r]
+}
+
+// Rules returns the rules of the given role which match the resource.
+func (sa *SubjectAccess) Rules(r RoleRef) []v1.PolicyRule {
+	return sa.roleToRules[r]
+}
+
+// Verbs returns the verbs which the given subject may perform on the resource.
+func (sa *SubjectAccess) Verbs(s SubjectRef) sets.String {
+	return sa.subjectToVerbs[s]
+}
+
 // MatchRules takes a RoleRef and a PolicyRule and adds the rule verbs to the
 // allowed verbs for the RoleRef, if the sa.resource matches the rule.
 // The RoleRef and rule usually come from a (Cluster)Role.
@@ -99,8 +159,10 @@ func (sa *SubjectAccess) MatchRules(ref RoleRef, rule v1.PolicyRule) {
 		return
 	}
 
+	matched := false
 	for _, r := range rule.Resources {
 		if r == v1.ResourceAll || r == sa.Resource {
+			matched = true
 			expandedVerbs := expand(rule.Verbs)
 			if verbs, ok := sa.roleToVerbs[ref]; ok {
 				sa.roleToVerbs[ref] = sets.NewString(expandedVerbs...).Union(verbs)
@@ -109,6 +171,14 @@ func (sa *SubjectAccess) MatchRules(ref RoleRef, rule v1.PolicyRule) {
 			}
 		}
 	}
+	if matched {
+		sa.roleToRules[ref] = append(sa.roleToRules[ref], rule)
+	}
+}
+
+// RuleVerbs returns the verbs of the rule, where the wildcard is expanded.
+func RuleVerbs(rule v1.PolicyRule) sets.String {
+	return sets.NewString(expand(rule.Verbs)...)
 }
 
 func includes(coll []string, x string) bool {
@@ -132,7 +202,8 @@ func expand(verbs []string) []string {
 	return verbs
 }
 
-func (sa *SubjectAccess) Table(verbs []string) *printer.Table {
+// Subjects returns all subjects with access, sorted by name and kind.
+func (sa *SubjectAccess) Subjects() []SubjectRef {
 	subjects := make([]SubjectRef, 0, len(sa.subjectToVerbs))
 	for s := range sa.subjectToVerbs {
 		subjects = append(subjects, s)
@@ -144,15 +215,22 @@ func (sa *SubjectAccess) Table(verbs []string) *printer.Table {
 		}
 		return comp < 0
 	})
+	return subjects
+}
 
+func (sa *SubjectAccess) Table(verbs []string) *printer.Table {
 	headers := []string{"NAME", "KIND", "SA-NAMESPACE"}
 	for _, v := range verbs {
 		headers = append(headers, strings.ToUpper(v))
 	}
 	p := printer.TableWithHeaders(headers)
+	p.Legend = map[printer.Outcome]string{
+		printer.Up:   legend[printer.Up],
+		printer.Down: legend[printer.Down],
+	}
 
 	// table body
-	for _, s := range subjects {
+	for _, s := range sa.Subjects() {
 		valid := sa.subjectToVerbs[s]
 		if !valid.HasAny(verbs...) {
 			continue
@@ -171,3 +249,33 @@ func (sa *SubjectAccess) Table(verbs []string) *printer.Table {
 
 	return p
 }
+
+// Matrix converts the result into its machine-readable representation.
+func (sa *SubjectAccess) Matrix(verbs []string) *AccessMatrix {
+	m := newAccessMatrix(verbs)
+	m.Spec.Resource = sa.Resource
+	m.Spec.ResourceName = sa.ResourceName
+
+	for _, s := range sa.Subjects() {
+		valid := sa.subjectToVerbs[s]
+		if !valid.HasAny(verbs...) {
+			continue
+		}
+		subject := s
+		for _, v := range verbs {
+			a := Denied
+			if valid.Has(v) {
+				a = Allowed
+			}
+			m.Status.Cells = append(m.Status.Cells, Cell{
+				Subject:   &subject,
+				Resource:  sa.Resource,
+				Group:     sa.Group,
+				Namespace: sa.Namespace,
+				Verb:      v,
+				State:     a.String(),
+			})
+		}
+	}
+	return m
+}
@@ -117,9 +117,11 @@ func TestSubjectAccess_MatchRules(t *testing.T) {
 
 			if test.expectedVerbs != nil {
 				assert.Equal(t, sets.NewString(test.expectedVerbs...), sa.roleToVerbs[r])
+				assert.Equal(t, []v1.PolicyRule{test.rule}, sa.Rules(r))
 			} else {
 				_, ok := sa.roleToVerbs[r]
 				assert.False(t, ok)
+				assert.Empty(t, sa.Rules(r))
 			}
 		})
 	}
@@ -187,3 +189,21 @@ func TestSubjectAccess_ResolveRoleRef(t *testing.T) {
 		})
 	}
 }
+
+func TestSubjectAccess_ResolveBinding(t *testing.T) {
+	matching := RoleRef{Name: "matching-role", Kind: "ClusterRole"}
+	other := RoleRef{Name: "other-role", Kind: "ClusterRole"}
+	b := BindingRef{Name: "some-binding", Kind: "ClusterRoleBinding"}
+	subject := SubjectRef{Name: "main", Kind: "User"}
+
+	sa := NewSubjectAccess("deployments", "")
+	sa.roleToVerbs[matching] = sets.NewString("get")
+
+	subjects := []v1.Subject{{Name: "main", Kind: "User"}}
+	sa.ResolveBinding(b, matching, subjects)
+	sa.ResolveBinding(b, other, subjects)
+
+	assert.Equal(t, []Grant{{Binding: b, Role: matching}}, sa.Grants(subject))
+	assert.Equal(t, sets.NewString("get"), sa.Verbs(subject))
+	assert.Equal(t, sets.NewString("get"), sa.RoleVerbs(matching))
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// writeVerbs are the verbs which modify a resource.
+var writeVerbs = sets.NewString("create", "update", "patch", "delete", "deletecollection")
+
+// Summary tallies the access results per verb and overall. A resource counts
+// as writable, if any of the given write verbs is allowed.
+func (ra ResourceAccess) Summary(verbs []string) *printer.Summary {
+	s := printer.NewSummary(verbs)
+	s.Rows = len(ra)
+	for _, r := range ra {
+		writable := false
+		for _, v := range verbs {
+			s.Add(v, r.Access[v].outcome())
+			if r.Access[v] == Allowed && writeVerbs.Has(v) {
+				writable = true
+			}
+		}
+		if writable {
+			s.WriteRows++
+		}
+	}
+	return s
+}
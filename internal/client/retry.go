@@ -0,0 +1,77 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const maxRetryDelay = 5 * time.Second
+
+var (
+	// for testing
+	retryDelay = 200 * time.Millisecond
+)
+
+// classify determines the cause of a failed access review.
+func classify(err error) result.ErrorClass {
+	var netErr net.Error
+	switch {
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return result.ErrForbidden
+	case apierrors.IsNotFound(err):
+		return result.ErrNotFound
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return result.ErrTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return result.ErrTimeout
+	case isServerError(err), apierrors.IsTooManyRequests(err):
+		return result.ErrServer
+	default:
+		return result.ErrOther
+	}
+}
+
+// isServerError checks if the API server answered with a 5xx status code.
+func isServerError(err error) bool {
+	var status apierrors.APIStatus
+	return errors.As(err, &status) && status.Status().Code >= 500
+}
+
+// isTransient checks if a failed access review may succeed when repeated.
+func isTransient(err error) bool {
+	class := classify(err)
+	return class == result.ErrTimeout || class == result.ErrServer
+}
+
+// sleep blocks for the given duration, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
+	authTesting "k8s.io/client-go/testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassify(t *testing.T) {
+	sar := schema.GroupResource{Group: "authorization.k8s.io", Resource: "selfsubjectaccessreviews"}
+	tests := []struct {
+		name     string
+		err      error
+		expected result.ErrorClass
+	}{
+		{name: "forbidden", err: apierrors.NewForbidden(sar, "", errors.New("no")), expected: result.ErrForbidden},
+		{name: "unauthorized", err: apierrors.NewUnauthorized("who are you"), expected: result.ErrForbidden},
+		{name: "not found", err: apierrors.NewNotFound(sar, ""), expected: result.ErrNotFound},
+		{name: "server timeout", err: apierrors.NewServerTimeout(sar, "create", 1), expected: result.ErrTimeout},
+		{name: "deadline", err: fmt.Errorf("wrapped: %w", context.DeadlineExceeded), expected: result.ErrTimeout},
+		{name: "client timeout", err: &url.Error{Op: "Post", URL: "https://k8s", Err: timeoutError{}}, expected: result.ErrTimeout},
+		{name: "internal error", err: apierrors.NewInternalError(errors.New("boom")), expected: result.ErrServer},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("aggregated API down"), expected: result.ErrServer},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("slow down", 0), expected: result.ErrServer},
+		{name: "other", err: errors.New("connection refused"), expected: result.ErrOther},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, classify(test.err))
+		})
+	}
+}
+
+func TestCheckResourceAccess_Retries(t *testing.T) {
+	defer func(d time.Duration) { retryDelay = d }(retryDelay)
+	retryDelay = time.Millisecond
+
+	tests := []struct {
+		name          string
+		err           error
+		failures      int32
+		retries       int
+		expected      result.Access
+		expectedCalls int32
+		expectedClass result.ErrorClass
+	}{
+		{
+			name:          "transient failure",
+			err:           apierrors.NewServiceUnavailable("aggregated API down"),
+			failures:      2,
+			retries:       3,
+			expected:      result.Allowed,
+			expectedCalls: 3,
+		},
+		{
+			name:          "too many failures",
+			err:           apierrors.NewServiceUnavailable("aggregated API down"),
+			failures:      5,
+			retries:       2,
+			expected:      result.RequestErr,
+			expectedCalls: 3,
+			expectedClass: result.ErrServer,
+		},
+		{
+			name:          "permanent failure",
+			err:           apierrors.NewUnauthorized("who are you"),
+			failures:      5,
+			retries:       3,
+			expected:      result.RequestErr,
+			expectedCalls: 1,
+			expectedClass: result.ErrForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls int32
+			fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+			fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+				func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+					if atomic.AddInt32(&calls, 1) <= test.failures {
+						return true, nil, test.err
+					}
+					sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+					sar.Status.Allowed = true
+					return true, sar, nil
+				})
+
+			input := []GroupResource{toGroupResource("", "pods", "list")}
+
+			results := CheckResourceAccess(context.Background(), fakeReviews, input, []string{"list"}, nil, 1, test.retries, nil)
+
+			assert.Equal(t, test.expected, results["pods"].Access["list"])
+			assert.Equal(t, test.expectedCalls, atomic.LoadInt32(&calls))
+			if test.expectedClass != "" {
+				d := results["pods"].Decisions["list"]
+				assert.Equal(t, test.expectedClass, d.ErrorClass)
+				assert.Equal(t, test.err.Error(), d.Error)
+			} else {
+				assert.Nil(t, results["pods"].Decisions)
+			}
+		})
+	}
+}
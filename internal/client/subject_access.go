@@ -32,8 +32,10 @@ var (
 )
 
 const (
-	clusterRoleName = "ClusterRole"
-	roleName        = "Role"
+	clusterRoleName        = "ClusterRole"
+	roleName               = "Role"
+	clusterRoleBindingName = "ClusterRoleBinding"
+	roleBindingName        = "RoleBinding"
 )
 
 // GetSubjectAccess determines subjects with access to the given resource.
@@ -47,6 +49,9 @@ func GetSubjectAccess(ctx context.Context, opts *options.RakkessOptions, resourc
 	isNamespace := namespace != nil && *namespace != ""
 
 	sa := result.NewSubjectAccess(resource, resourceName)
+	if isNamespace {
+		sa.Namespace = *namespace
+	}
 
 	if err := fetchMatchingClusterRoles(ctx, rbacClient, sa); err != nil {
 		if !isNamespace {
@@ -82,11 +87,16 @@ func resolveRoleBindings(ctx context.Context, cli clientv1.RoleBindingsGetter, s
 		return err
 	}
 	for _, rb := range roleBindings.Items {
+		b := result.BindingRef{
+			Name:      rb.Name,
+			Kind:      roleBindingName,
+			Namespace: namespace,
+		}
 		r := result.RoleRef{
 			Name: rb.RoleRef.Name,
 			Kind: rb.RoleRef.Kind,
 		}
-		sa.ResolveRoleRef(r, rb.Subjects)
+		sa.ResolveBinding(b, r, rb.Subjects)
 	}
 	return nil
 }
@@ -98,11 +108,15 @@ func resolveClusterRoleBindings(ctx context.Context, cli clientv1.ClusterRoleBin
 		return err
 	}
 	for _, crb := range clusterRoleBindings.Items {
+		b := result.BindingRef{
+			Name: crb.Name,
+			Kind: clusterRoleBindingName,
+		}
 		r := result.RoleRef{
 			Name: crb.RoleRef.Name,
 			Kind: crb.RoleRef.Kind,
 		}
-		sa.ResolveRoleRef(r, crb.Subjects)
+		sa.ResolveBinding(b, r, crb.Subjects)
 	}
 	return nil
 }
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	minThrottleDelay = 100 * time.Millisecond
+	maxThrottleDelay = 10 * time.Second
+	// maxThrottleRetries is the number of times a request is repeated after
+	// the API server rejected it with 429 (Too Many Requests).
+	maxThrottleRetries = 5
+)
+
+// throttle slows down all workers, when the API server asks clients to back
+// off. The delay doubles with every rejected request and halves with every
+// successful request.
+type throttle struct {
+	mu    sync.Mutex // guards delay
+	delay time.Duration
+}
+
+// wait blocks for the current delay, or until the context is done.
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	d := t.delay
+	t.mu.Unlock()
+	if d == 0 {
+		return ctx.Err()
+	}
+	return sleep(ctx, d)
+}
+
+// backoff increases the delay, if err says that the API server is
+// overloaded. It reports whether the request should be repeated.
+func (t *throttle) backoff(err error) bool {
+	if !apierrors.IsTooManyRequests(err) {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.delay *= 2
+	if t.delay < minThrottleDelay {
+		t.delay = minThrottleDelay
+	}
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+		if suggested := time.Duration(seconds) * time.Second; suggested > t.delay {
+			t.delay = suggested
+		}
+	}
+	if t.delay > maxThrottleDelay {
+		t.delay = maxThrottleDelay
+	}
+	klog.V(2).Infof("API server is overloaded, delaying requests by %s", t.delay)
+	return true
+}
+
+// relax decreases the delay after a successful request.
+func (t *throttle) relax() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.delay /= 2
+	if t.delay < minThrottleDelay {
+		t.delay = 0
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestThrottle(t *testing.T) {
+	th := &throttle{}
+
+	assert.False(t, th.backoff(errors.New("connection refused")))
+	assert.Equal(t, time.Duration(0), th.delay)
+
+	assert.True(t, th.backoff(apierrors.NewTooManyRequests("slow down", 0)))
+	assert.Equal(t, minThrottleDelay, th.delay)
+	assert.True(t, th.backoff(apierrors.NewTooManyRequests("slow down", 0)))
+	assert.Equal(t, 2*minThrottleDelay, th.delay)
+
+	assert.True(t, th.backoff(apierrors.NewTooManyRequests("slow down", 3)))
+	assert.Equal(t, 3*time.Second, th.delay)
+	assert.True(t, th.backoff(apierrors.NewTooManyRequests("slow down", 60)))
+	assert.Equal(t, maxThrottleDelay, th.delay)
+
+	th.delay = 2 * minThrottleDelay
+	th.relax()
+	assert.Equal(t, minThrottleDelay, th.delay)
+	th.relax()
+	assert.Equal(t, time.Duration(0), th.delay)
+}
+
+func TestThrottle_WaitCancelled(t *testing.T) {
+	th := &throttle{delay: maxThrottleDelay}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, context.Canceled, th.wait(ctx))
+}
@@ -23,6 +23,23 @@ const (
 	FlagOutput         = "output"
 	FlagVerbosity      = "verbosity"
 	FlagDiffWith       = "diff-with"
+	FlagExpect         = "expect"
+	FlagTranspose      = "transpose"
+	FlagRotateHeaders  = "rotate-headers"
+	FlagSortBy         = "sort-by"
+	FlagGroupBy        = "group-by"
+	FlagSummary        = "summary"
+	FlagSummaryOnly    = "summary-only"
+	FlagShowReasons    = "show-reasons"
+	FlagColor          = "color"
+	FlagTheme          = "theme"
+	FlagStream         = "stream"
+	FlagConcurrency    = "concurrency"
+	FlagQPS            = "qps"
+	FlagBurst          = "burst"
+	FlagTimeout        = "timeout"
+	FlagRetries        = "retries"
+	FlagSubresources   = "include-subresources"
 )
 
 var (
@@ -39,9 +56,84 @@ var (
 		"deletecollection",
 	}
 
+	// ValidNonResourceVerbs is the list of allowed actions on non-resource URLs.
+	ValidNonResourceVerbs = []string{
+		"get",
+		"head",
+		"post",
+		"put",
+		"patch",
+		"delete",
+		"options",
+	}
+
+	// DefaultNonResourceVerbs are the actions on non-resource URLs which are
+	// checked by default.
+	DefaultNonResourceVerbs = []string{
+		"get",
+		"post",
+		"put",
+		"delete",
+	}
+
+	// DefaultNonResourceURLs are the well-known non-resource URLs which are
+	// checked, if no paths are given.
+	DefaultNonResourceURLs = []string{
+		"/metrics",
+		"/healthz",
+		"/livez",
+		"/readyz",
+		"/version",
+		"/openapi/v2",
+		"/logs",
+	}
+
 	// ValidOutputFormats is the list of valid formats for the result table.
 	ValidOutputFormats = []string{
 		"icon-table",
 		"ascii-table",
+		"wide",
+		"compact",
+		"json",
+		"yaml",
+		"csv",
+		"tsv",
+		"markdown",
+		"html",
+		"junit",
+		"sarif",
+		"dot",
+		"graph-json",
+		"openmetrics",
+		"prometheus",
+	}
+
+	// ValidSortKeys is the list of valid orderings of the table rows.
+	ValidSortKeys = []string{
+		"name",
+		"group",
+		"allowed-count",
+		"denied-count",
+	}
+
+	// ValidGroupings is the list of valid groupings of the table rows.
+	ValidGroupings = []string{
+		"api-group",
+	}
+
+	// ValidColorModes is the list of valid settings for colored output.
+	ValidColorModes = []string{
+		"auto",
+		"always",
+		"never",
+	}
+
+	// ValidTemplateFormats is the list of valid output formats which take a
+	// template argument, e.g. 'jsonpath={.status}'.
+	ValidTemplateFormats = []string{
+		"go-template",
+		"go-template-file",
+		"jsonpath",
+		"jsonpath-file",
 	}
 )
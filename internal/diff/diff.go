@@ -26,7 +26,8 @@ import (
 )
 
 // Diff takes two result sets and produces a printer that contains only the
-// diff.
+// diff. Cells whose access review failed or was cancelled on either side, and
+// resources which were not checked on the right side, are not compared.
 func Diff(left, right result.ResourceAccess, verbs []string) *printer.Table {
 	// table header
 	headers := []string{"NAME"}
@@ -41,32 +42,51 @@ func Diff(left, right result.ResourceAccess, verbs []string) *printer.Table {
 	sort.Strings(names)
 
 	p := printer.TableWithHeaders(headers)
+	p.Diff = true
+	p.Legend = map[printer.Outcome]string{
+		printer.Up:   "access gained with the modified settings",
+		printer.Down: "access lost with the modified settings",
+		printer.None: "unchanged",
+	}
 
+	incomparable := false
 	for _, name := range names {
-		l, r := left[name], right[name]
+		if _, ok := right[name]; !ok {
+			incomparable = true
+			continue
+		}
+		l, r := left[name].Access, right[name].Access
 		klog.V(3).Infof("left=%v right=%v name=%s", l, r, name)
 
 		skip := true
 		var outcomes []printer.Outcome
 		for _, verb := range verbs {
-			ll, rr := l[verb], r[verb]
+			if unknown(l[verb]) || unknown(r[verb]) {
+				incomparable = true
+				outcomes = append(outcomes, printer.None)
+				continue
+			}
+			// only a change of the allowed state is a diff, e.g. an explicit
+			// deny on one side and no opinion on the other is not
+			ll, rr := l[verb] == result.Allowed, r[verb] == result.Allowed
 			var o printer.Outcome
 			if ll != rr {
 				skip = false
-				if ll == result.Allowed {
-					o = printer.Down
-				}
-				if rr == result.Allowed {
+				o = printer.Down
+				if rr {
 					o = printer.Up
 				}
 			}
 			outcomes = append(outcomes, o)
 		}
 		if !skip {
-			p.AddRow([]string{name}, outcomes...)
+			p.AddRowInSection(left[name].Section(), []string{name}, outcomes...)
 		}
 	}
 
+	if incomparable {
+		klog.Warning("Some access rights could not be compared, because their access reviews failed or did not finish.")
+	}
 	for name := range right {
 		if _, ok := left[name]; !ok {
 			klog.Warning("Some differences may be hidden, please swap the roles to get the full picture.")
@@ -76,3 +96,8 @@ func Diff(left, right result.ResourceAccess, verbs []string) *printer.Table {
 
 	return p
 }
+
+// unknown checks if the access review did not come to a decision.
+func unknown(a result.Access) bool {
+	return a == result.RequestErr || a == result.Cancelled
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	left := result.ResourceAccess{
+		"configmaps": {Name: "configmaps", Access: map[string]result.Access{"list": result.Allowed, "delete": result.Denied}},
+		"pods":       {Name: "pods", Access: map[string]result.Access{"list": result.Allowed, "delete": result.Allowed}},
+	}
+	right := result.ResourceAccess{
+		"configmaps": {Name: "configmaps", Access: map[string]result.Access{"list": result.Denied, "delete": result.Allowed}},
+		"pods":       {Name: "pods", Access: map[string]result.Access{"list": result.Allowed, "delete": result.Allowed}},
+	}
+
+	table := Diff(left, right, []string{"list", "delete"})
+
+	assert.Equal(t, []string{"NAME", "LIST", "DELETE"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"configmaps"}, Entries: []printer.Outcome{printer.Down, printer.Up}, Section: "core"},
+	}, table.Rows)
+}
+
+func TestDiff_ExplicitlyDenied(t *testing.T) {
+	left := result.ResourceAccess{
+		"secrets": {Name: "secrets", Access: map[string]result.Access{"list": result.ExplicitlyDenied, "delete": result.RequestErr}},
+		"pods":    {Name: "pods", Access: map[string]result.Access{"list": result.ExplicitlyDenied, "delete": result.Allowed}},
+	}
+	right := result.ResourceAccess{
+		"secrets": {Name: "secrets", Access: map[string]result.Access{"list": result.Denied, "delete": result.Denied}},
+		"pods":    {Name: "pods", Access: map[string]result.Access{"list": result.Allowed, "delete": result.ExplicitlyDenied}},
+	}
+
+	table := Diff(left, right, []string{"list", "delete"})
+
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"pods"}, Entries: []printer.Outcome{printer.Up, printer.Down}, Section: "core"},
+	}, table.Rows)
+}
+
+func TestDiff_PartialRight(t *testing.T) {
+	left := result.ResourceAccess{
+		"configmaps": {Name: "configmaps", Access: map[string]result.Access{"list": result.Allowed, "delete": result.Allowed}},
+		"pods":       {Name: "pods", Access: map[string]result.Access{"list": result.Allowed, "delete": result.Allowed}},
+		"secrets":    {Name: "secrets", Access: map[string]result.Access{"list": result.Allowed, "delete": result.Allowed}},
+	}
+	right := result.ResourceAccess{
+		"configmaps": {Name: "configmaps", Access: map[string]result.Access{"list": result.Denied, "delete": result.Cancelled}},
+		"pods":       {Name: "pods", Access: map[string]result.Access{"list": result.RequestErr, "delete": result.Cancelled}},
+	}
+
+	table := Diff(left, right, []string{"list", "delete"})
+
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"configmaps"}, Entries: []printer.Outcome{printer.Down, printer.None}, Section: "core"},
+	}, table.Rows)
+}
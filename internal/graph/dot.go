@@ -0,0 +1,80 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+)
+
+// shapes distinguishes the node kinds in the rendered graph.
+var shapes = map[string]string{
+	"User":               "ellipse",
+	"Group":              "ellipse",
+	"ServiceAccount":     "ellipse",
+	"RoleBinding":        "box",
+	"ClusterRoleBinding": "box",
+	"Role":               "box, style=rounded",
+	"ClusterRole":        "box, style=rounded",
+	KindRule:             "note",
+	KindResource:         "box3d",
+}
+
+// printDOT writes the graph in the Graphviz DOT language, from left to right.
+func (g *Graph) printDOT(out io.Writer) {
+	fmt.Fprintf(out, "digraph %s {\n", strconv.Quote(constants.CommandName))
+	fmt.Fprintln(out, "  rankdir=LR;")
+	for _, n := range g.Nodes {
+		shape, ok := shapes[n.Kind]
+		if !ok {
+			shape = "ellipse"
+		}
+		fmt.Fprintf(out, "  %s [label=%s, shape=%s];\n", strconv.Quote(n.ID), strconv.Quote(n.label()), shape)
+	}
+	for _, e := range g.Edges {
+		if e.Label == "" {
+			fmt.Fprintf(out, "  %s -> %s;\n", strconv.Quote(e.From), strconv.Quote(e.To))
+		} else {
+			fmt.Fprintf(out, "  %s -> %s [label=%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), strconv.Quote(e.Label))
+		}
+	}
+	fmt.Fprintln(out, "}")
+}
+
+// label is the text of the node in the rendered graph. Rules show their
+// verbs and resources instead of a name.
+func (n Node) label() string {
+	if n.Rule != nil {
+		lines := []string{
+			"verbs: " + strings.Join(n.Rule.Verbs, ","),
+			"resources: " + strings.Join(n.Rule.Resources, ","),
+		}
+		if len(n.Rule.ResourceNames) > 0 {
+			lines = append(lines, "resourceNames: "+strings.Join(n.Rule.ResourceNames, ","))
+		}
+		return strings.Join(lines, "\n")
+	}
+	name := n.Name
+	if n.Namespace != "" && n.Kind != KindResource {
+		name = n.Namespace + "/" + n.Name
+	}
+	return n.Kind + "\n" + name
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Node kinds besides the kinds of subjects, bindings, and roles.
+const (
+	KindRule     = "Rule"
+	KindResource = "Resource"
+)
+
+// Graph shows why subjects have access to a resource. It links every subject
+// to its bindings, the bound roles, the matching rules of the roles, and
+// finally the resource.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	seen map[string]bool
+}
+
+// Node is a subject, binding, role, rule, or resource.
+type Node struct {
+	ID        string         `json:"id"`
+	Kind      string         `json:"kind"`
+	Name      string         `json:"name"`
+	Namespace string         `json:"namespace,omitempty"`
+	Rule      *v1.PolicyRule `json:"rule,omitempty"`
+}
+
+// Edge connects two nodes. The label optionally holds the verbs.
+type Edge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label,omitempty"`
+}
+
+// IsFormat checks if the output format prints a graph.
+func IsFormat(outputFormat string) bool {
+	return outputFormat == "dot" || outputFormat == "graph-json"
+}
+
+// FromSubjects builds the graph for all subjects which may perform any of
+// the given verbs on the resource.
+func FromSubjects(sa *result.SubjectAccess, verbs []string) *Graph {
+	g := &Graph{
+		Nodes: []Node{},
+		Edges: []Edge{},
+		seen:  make(map[string]bool),
+	}
+
+	resourceName := sa.Resource
+	if sa.Group != "" {
+		resourceName = fmt.Sprintf("%s.%s", sa.Resource, sa.Group)
+	}
+	if sa.ResourceName != "" {
+		resourceName = fmt.Sprintf("%s/%s", resourceName, sa.ResourceName)
+	}
+	resource := Node{ID: nodeID(KindResource, sa.Namespace, resourceName), Kind: KindResource, Name: resourceName, Namespace: sa.Namespace}
+
+	for _, s := range sa.Subjects() {
+		for _, grant := range sa.Grants(s) {
+			if !sa.RoleVerbs(grant.Role).HasAny(verbs...) {
+				continue
+			}
+
+			subject := Node{ID: nodeID(s.Kind, s.Namespace, s.Name), Kind: s.Kind, Name: s.Name, Namespace: s.Namespace}
+			binding := Node{ID: nodeID(grant.Binding.Kind, grant.Binding.Namespace, grant.Binding.Name), Kind: grant.Binding.Kind, Name: grant.Binding.Name, Namespace: grant.Binding.Namespace}
+			role := Node{Kind: grant.Role.Kind, Name: grant.Role.Name}
+			if grant.Role.Kind == "Role" {
+				role.Namespace = grant.Binding.Namespace
+			}
+			role.ID = nodeID(role.Kind, role.Namespace, role.Name)
+
+			g.addNode(subject)
+			g.addNode(binding)
+			g.addNode(role)
+			g.addEdge(Edge{From: subject.ID, To: binding.ID})
+			g.addEdge(Edge{From: binding.ID, To: role.ID})
+
+			for i, r := range sa.Rules(grant.Role) {
+				allowed := result.RuleVerbs(r).Intersection(sets.NewString(verbs...))
+				if allowed.Len() == 0 {
+					continue
+				}
+				rule := r
+				ruleNode := Node{ID: fmt.Sprintf("%s#%d", role.ID, i+1), Kind: KindRule, Name: fmt.Sprintf("rule %d", i+1), Namespace: role.Namespace, Rule: &rule}
+				g.addNode(ruleNode)
+				g.addNode(resource)
+				g.addEdge(Edge{From: role.ID, To: ruleNode.ID})
+				g.addEdge(Edge{From: ruleNode.ID, To: resource.ID, Label: strings.Join(allowed.List(), ",")})
+			}
+		}
+	}
+	return g
+}
+
+func (g *Graph) addNode(n Node) {
+	if g.seen[n.ID] {
+		return
+	}
+	g.seen[n.ID] = true
+	g.Nodes = append(g.Nodes, n)
+}
+
+func (g *Graph) addEdge(e Edge) {
+	id := e.From + " -> " + e.To
+	if g.seen[id] {
+		return
+	}
+	g.seen[id] = true
+	g.Edges = append(g.Edges, e)
+}
+
+func nodeID(kind, namespace, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s/%s", kind, name)
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// Print writes the graph in the given output format, which is either 'dot'
+// or 'graph-json'.
+func (g *Graph) Print(out io.Writer, outputFormat string) error {
+	if outputFormat == "dot" {
+		g.printDOT(out)
+		return nil
+	}
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/rbac/v1"
+)
+
+func testSubjectAccess() *result.SubjectAccess {
+	sa := result.NewSubjectAccess("secrets", "")
+	sa.Namespace = "default"
+
+	reader := result.RoleRef{Name: "secret-reader", Kind: "Role"}
+	sa.MatchRules(reader, v1.PolicyRule{Verbs: []string{"get", "list"}, Resources: []string{"secrets"}})
+	sa.MatchRules(reader, v1.PolicyRule{Verbs: []string{"get"}, Resources: []string{"configmaps"}})
+	sa.MatchRules(reader, v1.PolicyRule{Verbs: []string{"delete"}, Resources: []string{"secrets"}})
+	admin := result.RoleRef{Name: "admin", Kind: "ClusterRole"}
+	sa.MatchRules(admin, v1.PolicyRule{Verbs: []string{"*"}, Resources: []string{"*"}})
+
+	sa.ResolveBinding(
+		result.BindingRef{Name: "read-secrets", Kind: "RoleBinding", Namespace: "default"},
+		reader,
+		[]v1.Subject{{Kind: "ServiceAccount", Name: "app", Namespace: "default"}},
+	)
+	sa.ResolveBinding(
+		result.BindingRef{Name: "admins", Kind: "ClusterRoleBinding"},
+		admin,
+		[]v1.Subject{{Kind: "User", Name: "alice"}},
+	)
+	return sa
+}
+
+func TestFromSubjects(t *testing.T) {
+	g := FromSubjects(testSubjectAccess(), []string{"list"})
+
+	var ids []string
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	assert.Equal(t, []string{
+		"User/alice",
+		"ClusterRoleBinding/admins",
+		"ClusterRole/admin",
+		"ClusterRole/admin#1",
+		"Resource/default/secrets",
+		"ServiceAccount/default/app",
+		"RoleBinding/default/read-secrets",
+		"Role/default/secret-reader",
+		"Role/default/secret-reader#1",
+	}, ids)
+	assert.Equal(t, []Edge{
+		{From: "User/alice", To: "ClusterRoleBinding/admins"},
+		{From: "ClusterRoleBinding/admins", To: "ClusterRole/admin"},
+		{From: "ClusterRole/admin", To: "ClusterRole/admin#1"},
+		{From: "ClusterRole/admin#1", To: "Resource/default/secrets", Label: "list"},
+		{From: "ServiceAccount/default/app", To: "RoleBinding/default/read-secrets"},
+		{From: "RoleBinding/default/read-secrets", To: "Role/default/secret-reader"},
+		{From: "Role/default/secret-reader", To: "Role/default/secret-reader#1"},
+		{From: "Role/default/secret-reader#1", To: "Resource/default/secrets", Label: "list"},
+	}, g.Edges)
+}
+
+func TestFromSubjects_NoAccess(t *testing.T) {
+	g := FromSubjects(testSubjectAccess(), []string{"watch"})
+
+	var ids []string
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	assert.Equal(t, []string{"User/alice", "ClusterRoleBinding/admins", "ClusterRole/admin", "ClusterRole/admin#1", "Resource/default/secrets"}, ids)
+}
+
+func TestPrint_DOT(t *testing.T) {
+	sa := result.NewSubjectAccess("pods", "web")
+	role := result.RoleRef{Name: "view", Kind: "ClusterRole"}
+	sa.MatchRules(role, v1.PolicyRule{Verbs: []string{"get"}, Resources: []string{"pods"}, ResourceNames: []string{"web"}})
+	sa.ResolveBinding(result.BindingRef{Name: "viewers", Kind: "ClusterRoleBinding"}, role, []v1.Subject{{Kind: "Group", Name: "devs"}})
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, FromSubjects(sa, []string{"get"}).Print(buf, "dot"))
+	assert.Equal(t, `digraph `+strconv.Quote(constants.CommandName)+` {
+  rankdir=LR;
+  "Group/devs" [label="Group\ndevs", shape=ellipse];
+  "ClusterRoleBinding/viewers" [label="ClusterRoleBinding\nviewers", shape=box];
+  "ClusterRole/view" [label="ClusterRole\nview", shape=box, style=rounded];
+  "ClusterRole/view#1" [label="verbs: get\nresources: pods\nresourceNames: web", shape=note];
+  "Resource/pods/web" [label="Resource\npods/web", shape=box3d];
+  "Group/devs" -> "ClusterRoleBinding/viewers";
+  "ClusterRoleBinding/viewers" -> "ClusterRole/view";
+  "ClusterRole/view" -> "ClusterRole/view#1";
+  "ClusterRole/view#1" -> "Resource/pods/web" [label="get"];
+}
+`, buf.String())
+}
+
+func TestPrint_JSON(t *testing.T) {
+	sa := result.NewSubjectAccess("pods", "")
+	role := result.RoleRef{Name: "view", Kind: "ClusterRole"}
+	sa.MatchRules(role, v1.PolicyRule{Verbs: []string{"get"}, Resources: []string{"pods"}})
+	sa.ResolveBinding(result.BindingRef{Name: "viewers", Kind: "ClusterRoleBinding"}, role, []v1.Subject{{Kind: "Group", Name: "devs"}})
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, FromSubjects(sa, []string{"get"}).Print(buf, "graph-json"))
+	assert.JSONEq(t, `{
+  "nodes": [
+    {"id": "Group/devs", "kind": "Group", "name": "devs"},
+    {"id": "ClusterRoleBinding/viewers", "kind": "ClusterRoleBinding", "name": "viewers"},
+    {"id": "ClusterRole/view", "kind": "ClusterRole", "name": "view"},
+    {"id": "ClusterRole/view#1", "kind": "Rule", "name": "rule 1", "rule": {"verbs": ["get"], "resources": ["pods"]}},
+    {"id": "Resource/pods", "kind": "Resource", "name": "pods"}
+  ],
+  "edges": [
+    {"from": "Group/devs", "to": "ClusterRoleBinding/viewers"},
+    {"from": "ClusterRoleBinding/viewers", "to": "ClusterRole/view"},
+    {"from": "ClusterRole/view", "to": "ClusterRole/view#1"},
+    {"from": "ClusterRole/view#1", "to": "Resource/pods", "label": "get"}
+  ]
+}`, buf.String())
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+)
+
+const (
+	accessMetric = "rakkess_access"
+	errorsMetric = "rakkess_request_errors"
+
+	serviceAccountPrefix = "system:serviceaccount:"
+)
+
+// errorKey identifies the series of the request error gauge.
+type errorKey struct {
+	resource, group, namespace, subject, class string
+}
+
+// IsFormat checks if the output format prints metrics.
+func IsFormat(outputFormat string) bool {
+	return outputFormat == "openmetrics" || outputFormat == "prometheus"
+}
+
+// Print writes the access matrix as metrics in the given output format, which
+// is one of 'openmetrics' or 'prometheus'.
+func Print(out io.Writer, outputFormat string, m *result.AccessMatrix, subject string) error {
+	if outputFormat == "prometheus" {
+		return PrintPrometheus(out, m, subject)
+	}
+	return PrintOpenMetrics(out, m, subject)
+}
+
+// PrintOpenMetrics writes the access matrix in the OpenMetrics text format.
+// Every allowed or denied cell becomes a sample of the gauge rakkess_access
+// with value 1 or 0. Cells which are not applicable are left out, and failed
+// access reviews are counted per resource and error class in the gauge
+// rakkess_request_errors. It is no counter, because every run starts from
+// zero. The subject label is the given subject, if the matrix records none.
+func PrintOpenMetrics(out io.Writer, m *result.AccessMatrix, subject string) error {
+	return write(out, m, subject, true)
+}
+
+// PrintPrometheus is like PrintOpenMetrics, but writes the Prometheus text
+// format without EOF marker. This is the format of the textfile collector of
+// the node-exporter.
+func PrintPrometheus(out io.Writer, m *result.AccessMatrix, subject string) error {
+	return write(out, m, subject, false)
+}
+
+// write prints the metrics in the OpenMetrics or the Prometheus text format.
+func write(out io.Writer, m *result.AccessMatrix, defaultSubject string, openMetrics bool) error {
+	w := bufio.NewWriter(out)
+
+	fmt.Fprintf(w, "# HELP %s Whether the subject may perform the verb on the resource (1) or not (0).\n", accessMetric)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", accessMetric)
+	errors := make(map[errorKey]int)
+	for _, c := range m.Status.Cells {
+		subject := subjectLabel(m, c, defaultSubject)
+		var value int
+		switch c.State {
+		case result.Allowed.String():
+			value = 1
+		case result.Denied.String(), result.ExplicitlyDenied.String():
+			value = 0
+		case result.RequestErr.String():
+			errors[errorKey{c.Resource, c.Group, c.Namespace, subject, c.ErrorClass}]++
+			continue
+		default:
+			continue
+		}
+		fmt.Fprintf(w, "%s{resource=%s,group=%s,verb=%s,namespace=%s,subject=%s} %d\n", accessMetric,
+			quote(c.Resource), quote(c.Group), quote(c.Verb), quote(c.Namespace), quote(subject), value)
+	}
+
+	fmt.Fprintf(w, "# HELP %s Number of access reviews which failed.\n", errorsMetric)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", errorsMetric)
+	keys := make([]errorKey, 0, len(errors))
+	for k := range errors {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.subject != b.subject {
+			return a.subject < b.subject
+		}
+		if a.group != b.group {
+			return a.group < b.group
+		}
+		if a.resource != b.resource {
+			return a.resource < b.resource
+		}
+		return a.class < b.class
+	})
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{resource=%s,group=%s,namespace=%s,subject=%s,class=%s} %d\n", errorsMetric,
+			quote(k.resource), quote(k.group), quote(k.namespace), quote(k.subject), quote(k.class), errors[k])
+	}
+
+	if openMetrics {
+		fmt.Fprintln(w, "# EOF")
+	}
+	return w.Flush()
+}
+
+// subjectLabel names the subject of the cell like the API server does in
+// user info. Without a subject in the cell, this is the impersonated user,
+// or the given default subject if there is none.
+func subjectLabel(m *result.AccessMatrix, c result.Cell, defaultSubject string) string {
+	if c.Subject == nil {
+		if m.Spec.User == "" {
+			return defaultSubject
+		}
+		return m.Spec.User
+	}
+	if c.Subject.Kind == "ServiceAccount" {
+		return fmt.Sprintf("%s%s:%s", serviceAccountPrefix, c.Subject.Namespace, c.Subject.Name)
+	}
+	return c.Subject.Name
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote formats a label value.
+func quote(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
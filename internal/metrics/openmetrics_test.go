@@ -0,0 +1,140 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintOpenMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		matrix   *result.AccessMatrix
+		expected string
+	}{
+		{
+			name: "resource matrix",
+			matrix: &result.AccessMatrix{
+				Spec: result.MatrixSpec{User: "system:serviceaccount:ns:ci"},
+				Status: result.MatrixStatus{Cells: []result.Cell{
+					{Resource: "pods", Namespace: "ns", Verb: "get", State: "allowed"},
+					{Resource: "pods", Namespace: "ns", Verb: "delete", State: "denied"},
+					{Resource: "deployments", Group: "apps", Namespace: "ns", Verb: "get", State: "explicitly-denied"},
+					{Resource: "nodes", Namespace: "ns", Verb: "get", State: "not-applicable"},
+					{Resource: "metrics", Group: "metrics.k8s.io", Namespace: "ns", Verb: "get", State: "error", ErrorClass: "timeout"},
+					{Resource: "metrics", Group: "metrics.k8s.io", Namespace: "ns", Verb: "delete", State: "error", ErrorClass: "timeout"},
+				}},
+			},
+			expected: `# HELP rakkess_access Whether the subject may perform the verb on the resource (1) or not (0).
+# TYPE rakkess_access gauge
+rakkess_access{resource="pods",group="",verb="get",namespace="ns",subject="system:serviceaccount:ns:ci"} 1
+rakkess_access{resource="pods",group="",verb="delete",namespace="ns",subject="system:serviceaccount:ns:ci"} 0
+rakkess_access{resource="deployments",group="apps",verb="get",namespace="ns",subject="system:serviceaccount:ns:ci"} 0
+# HELP rakkess_request_errors Number of access reviews which failed.
+# TYPE rakkess_request_errors gauge
+rakkess_request_errors{resource="metrics",group="metrics.k8s.io",namespace="ns",subject="system:serviceaccount:ns:ci",class="timeout"} 2
+# EOF
+`,
+		},
+		{
+			name: "subject matrix",
+			matrix: &result.AccessMatrix{
+				Status: result.MatrixStatus{Cells: []result.Cell{
+					{Subject: &result.SubjectRef{Kind: "ServiceAccount", Name: "app", Namespace: "ns"}, Resource: "secrets", Verb: "list", State: "allowed"},
+					{Subject: &result.SubjectRef{Kind: "User", Name: `dr. "evil"`}, Resource: "secrets", Verb: "list", State: "allowed"},
+				}},
+			},
+			expected: `# HELP rakkess_access Whether the subject may perform the verb on the resource (1) or not (0).
+# TYPE rakkess_access gauge
+rakkess_access{resource="secrets",group="",verb="list",namespace="",subject="system:serviceaccount:ns:app"} 1
+rakkess_access{resource="secrets",group="",verb="list",namespace="",subject="dr. \"evil\""} 1
+# HELP rakkess_request_errors Number of access reviews which failed.
+# TYPE rakkess_request_errors gauge
+# EOF
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			assert.NoError(t, PrintOpenMetrics(buf, test.matrix, ""))
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestPrintPrometheus(t *testing.T) {
+	m := &result.AccessMatrix{
+		Spec: result.MatrixSpec{User: "alice"},
+		Status: result.MatrixStatus{Cells: []result.Cell{
+			{Resource: "pods", Verb: "get", State: "allowed"},
+			{Resource: "metrics", Group: "metrics.k8s.io", Verb: "get", State: "error", ErrorClass: "timeout"},
+		}},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, PrintPrometheus(buf, m, ""))
+	assert.Equal(t, `# HELP rakkess_access Whether the subject may perform the verb on the resource (1) or not (0).
+# TYPE rakkess_access gauge
+rakkess_access{resource="pods",group="",verb="get",namespace="",subject="alice"} 1
+# HELP rakkess_request_errors Number of access reviews which failed.
+# TYPE rakkess_request_errors gauge
+rakkess_request_errors{resource="metrics",group="metrics.k8s.io",namespace="",subject="alice",class="timeout"} 1
+`, buf.String())
+	assertTypedFamilies(t, buf.String())
+}
+
+// assertTypedFamilies checks that every sample belongs to the family of the
+// preceding TYPE line, as the Prometheus text format requires. Otherwise, the
+// textfile collector treats the samples as untyped.
+func assertTypedFamilies(t *testing.T, text string) {
+	var family string
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if strings.HasPrefix(line, "# TYPE ") {
+			family = strings.Fields(line)[2]
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name := line[:strings.IndexAny(line, "{ ")]
+		assert.Equal(t, family, name, "sample %q", line)
+	}
+}
+
+func TestPrintPrometheus_DefaultSubject(t *testing.T) {
+	m := &result.AccessMatrix{
+		Status: result.MatrixStatus{Cells: []result.Cell{
+			{Resource: "pods", Verb: "get", State: "allowed"},
+		}},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, PrintPrometheus(buf, m, "kind-admin"))
+	assert.Contains(t, buf.String(), `rakkess_access{resource="pods",group="",verb="get",namespace="",subject="kind-admin"} 1`+"\n")
+
+	m.Spec.User = "alice"
+	buf.Reset()
+	assert.NoError(t, PrintPrometheus(buf, m, "kind-admin"))
+	assert.Contains(t, buf.String(), `subject="alice"`)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/corneliusweig/rakkess/internal/constants"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -35,13 +36,37 @@ type RakkessOptions struct {
 	Verbs            []string
 	AsServiceAccount string
 	OutputFormat     string
+	Transpose        bool
+	RotateHeaders    bool
+	SortBy           string
+	GroupBy          string
+	Summary          bool
+	SummaryOnly      bool
+	ShowReasons      bool
+	Color            string
+	Theme            []string
+	Stream           bool
+	Concurrency      int
+	QPS              float32
+	Burst            int
+	Timeout          time.Duration
+	Retries          int
+	Subresources     bool
 	Streams          *genericclioptions.IOStreams
 }
 
+// defaultRequestTimeout bounds every single request, so that an unresponsive
+// aggregated API does not stall the whole review.
+const defaultRequestTimeout = "10s"
+
 // NewRakkessOptions creates RakkessOptions with defaults.
 func NewRakkessOptions() *RakkessOptions {
+	configFlags := genericclioptions.NewConfigFlags(false)
+	requestTimeout := defaultRequestTimeout
+	configFlags.Timeout = &requestTimeout
+
 	return &RakkessOptions{
-		ConfigFlags: genericclioptions.NewConfigFlags(false),
+		ConfigFlags: configFlags,
 		Streams: &genericclioptions.IOStreams{
 			In:     os.Stdin,
 			Out:    os.Stdout,
@@ -60,20 +85,52 @@ func NewTestRakkessOptions() (*RakkessOptions, *bytes.Buffer, *bytes.Buffer, *by
 	}, in, out, errout
 }
 
-// GetAuthClient creates a client for SelfSubjectAccessReviews with high queries per second.
+// GetAuthClient creates a client for SelfSubjectAccessReviews with the configured
+// queries per second and burst.
 func (o *RakkessOptions) GetAuthClient() (v1.SelfSubjectAccessReviewInterface, error) {
 	restConfig, err := o.ConfigFlags.ToRESTConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	restConfig.QPS = 500
-	restConfig.Burst = 1000
+	restConfig.QPS = o.QPS
+	restConfig.Burst = o.Burst
 
 	authClient := v1.NewForConfigOrDie(restConfig)
 	return authClient.SelfSubjectAccessReviews(), nil
 }
 
+// CurrentContext determines the kubeconfig context in use. It returns an
+// empty string if the kubeconfig cannot be loaded.
+func (o *RakkessOptions) CurrentContext() string {
+	if o.ConfigFlags.Context != nil && *o.ConfigFlags.Context != "" {
+		return *o.ConfigFlags.Context
+	}
+	rawConfig, err := o.ConfigFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		klog.V(2).Infof("Cannot determine current context: %s", err)
+		return ""
+	}
+	return rawConfig.CurrentContext
+}
+
+// CurrentUser determines the kubeconfig user of the context in use. It
+// returns an empty string if the kubeconfig cannot be loaded.
+func (o *RakkessOptions) CurrentUser() string {
+	if o.ConfigFlags.AuthInfoName != nil && *o.ConfigFlags.AuthInfoName != "" {
+		return *o.ConfigFlags.AuthInfoName
+	}
+	rawConfig, err := o.ConfigFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		klog.V(2).Infof("Cannot determine current user: %s", err)
+		return ""
+	}
+	if c, ok := rawConfig.Contexts[o.CurrentContext()]; ok {
+		return c.AuthInfo
+	}
+	return ""
+}
+
 // DiscoveryClient creates a kubernetes discovery client.
 func (o *RakkessOptions) DiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
 	return o.ConfigFlags.ToDiscoveryClient()
@@ -115,3 +172,13 @@ func (o *RakkessOptions) ExpandVerbs() {
 		}
 	}
 }
+
+// ExpandNonResourceVerbs is like ExpandVerbs, but expands to the verbs of
+// non-resource URLs.
+func (o *RakkessOptions) ExpandNonResourceVerbs() {
+	for _, verb := range o.Verbs {
+		if verb == "*" || verb == "all" {
+			o.Verbs = constants.ValidNonResourceVerbs
+		}
+	}
+}
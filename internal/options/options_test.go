@@ -62,6 +62,16 @@ func TestRakkessOptions_ExpandVerbs(t *testing.T) {
 	}
 }
 
+func TestRakkessOptions_ExpandNonResourceVerbs(t *testing.T) {
+	opts := &RakkessOptions{Verbs: []string{"get", "all"}}
+	opts.ExpandNonResourceVerbs()
+	assert.Equal(t, constants.ValidNonResourceVerbs, opts.Verbs)
+
+	opts = &RakkessOptions{Verbs: []string{"get", "post"}}
+	opts.ExpandNonResourceVerbs()
+	assert.Equal(t, []string{"get", "post"}, opts.Verbs)
+}
+
 func TestRakkessOptions_ExpandServiceAccount(t *testing.T) {
 	tests := []struct {
 		name           string
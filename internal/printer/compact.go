@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+)
+
+// verbLetters holds the abbreviations of verbs which do not start with a
+// unique letter.
+var verbLetters = map[string]string{
+	"deletecollection": "x",
+}
+
+// renderCompact prints one line per row, which starts with a fixed-width
+// permission string similar to Unix file modes, e.g. 'c g l - - - - -'.
+// Allowed verbs are shown with their first letter, denied verbs with a dash.
+// The columns are ordered like constants.ValidVerbs, and the header line
+// spells out the letter of each column. Notes are referenced after the name
+// and listed at the end.
+func (p *Table) renderCompact(out io.Writer) {
+	intro := p.introColumns()
+	columns := compactColumns(p.Headers[intro:])
+
+	letters := make([]string, 0, len(columns))
+	for _, c := range columns {
+		letters = append(letters, verbLetter(strings.ToLower(p.Headers[intro+c])))
+	}
+	fmt.Fprintf(out, "%s  %s\n", strings.Join(letters, " "), strings.Join(p.Headers[:intro], " "))
+
+	var notes footnotes
+	for _, row := range p.Rows {
+		cells := make([]string, 0, len(columns))
+		var refs string
+		for i, c := range columns {
+			cells = append(cells, compactSymbol(row.Entries[c], letters[i]))
+			if note := p.shownNote(row, c); note != "" {
+				refs += fmt.Sprintf(" [%d]", notes.add(note))
+			}
+		}
+		fmt.Fprintf(out, "%s  %s%s\n", strings.Join(cells, " "), rowName(row), refs)
+	}
+	notes.render(out)
+}
+
+// compactColumns orders the indices of the given verb headers like
+// constants.ValidVerbs. Unknown verbs are moved to the end.
+func compactColumns(headers []string) []int {
+	rank := make(map[string]int, len(constants.ValidVerbs))
+	for i, v := range constants.ValidVerbs {
+		rank[v] = i
+	}
+	rankOf := func(h string) int {
+		if r, ok := rank[strings.ToLower(h)]; ok {
+			return r
+		}
+		return len(constants.ValidVerbs)
+	}
+
+	columns := make([]int, 0, len(headers))
+	for i := range headers {
+		columns = append(columns, i)
+	}
+	sort.SliceStable(columns, func(i, j int) bool {
+		return rankOf(headers[columns[i]]) < rankOf(headers[columns[j]])
+	})
+	return columns
+}
+
+func verbLetter(verb string) string {
+	if l, ok := verbLetters[verb]; ok {
+		return l
+	}
+	if verb == "" {
+		return "?"
+	}
+	return verb[:1]
+}
+
+func compactSymbol(o Outcome, letter string) string {
+	switch o {
+	case Up:
+		return letter
+	case Down:
+		return "-"
+	case Deny:
+		return "!"
+	case Err:
+		return "E"
+	case Cancel:
+		return "?"
+	default:
+		return "."
+	}
+}
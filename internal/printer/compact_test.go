@@ -0,0 +1,64 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderCompact(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "DELETECOLLECTION", "LIST", "CREATE", "GET", "DELETE"},
+		Rows: []Row{
+			{Intro: []string{"configmaps"}, Entries: []Outcome{Up, Up, Up, Up, Up}},
+			{Intro: []string{"deployments.apps"}, Entries: []Outcome{Down, Up, Deny, Up, Down}},
+			{Intro: []string{"tokenreviews.authentication.k8s.io"}, Entries: []Outcome{None, None, Up, None, Err}},
+			{Intro: []string{"pods"}, Entries: []Outcome{Up, Up, Cancel, Cancel, Cancel}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "compact")
+	assert.Equal(t, `c g l d x  NAME
+c g l d x  configmaps
+! g l - -  deployments.apps
+c . . E .  tokenreviews.authentication.k8s.io
+? ? l ? x  pods
+`, buf.String())
+}
+
+func TestRenderCompact_Notes(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "LIST", "GET"},
+		Rows: []Row{
+			{Intro: []string{"configmaps"}, Entries: []Outcome{Up, Up}, Notes: []string{"allowed by view", "allowed by view"}},
+			{Intro: []string{"metrics"}, Entries: []Outcome{Err, Up}, Notes: []string{"timeout: context deadline exceeded", ""}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "compact")
+	assert.Equal(t, `g l  NAME
+g l  configmaps
+g E  metrics [1]
+
+[1] timeout: context deadline exceeded
+`, buf.String())
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// padding is the space between two table columns.
+	padding = 2
+	// minHeaderWidth is the width of wrapped headers, so that most verbs
+	// still fit on a single line.
+	minHeaderWidth = 6
+	// minIntroWidth is the width below which intro columns are not truncated.
+	minIntroWidth = 12
+	// ellipsis replaces the middle part of truncated names.
+	ellipsis = "…"
+)
+
+var (
+	// for testing
+	terminalSize = terminalSizeImpl
+	runPager     = runPagerImpl
+)
+
+// fit shrinks the table to the given width. First, the headers of the
+// outcome columns are wrapped onto several lines. If the table is still too
+// wide, the widest intro columns are truncated in the middle, so that both
+// the resource name and the end of the API group remain visible.
+func (p *Table) fit(width int, headers, intros [][]string, symbol func(Outcome) string) ([][]string, [][]string) {
+	intro := p.introColumns()
+	entryWidth := 1
+	for _, row := range p.Rows {
+		for _, e := range row.Entries {
+			if n := runeLen(symbol(e)); n > entryWidth {
+				entryWidth = n
+			}
+		}
+	}
+
+	widths := columnWidths(headers, intros, intro, entryWidth)
+	if tableWidth(widths) <= width {
+		return headers, intros
+	}
+
+	if !p.RotateHeaders {
+		size := minHeaderWidth
+		if entryWidth > size {
+			size = entryWidth
+		}
+		headers = p.wrappedHeaders(size)
+		widths = columnWidths(headers, intros, intro, entryWidth)
+	}
+
+	excess := tableWidth(widths) - width
+	for excess > 0 {
+		widest := -1
+		for i := 0; i < intro; i++ {
+			if widths[i] > minIntroWidth && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		excess--
+	}
+
+	truncated := make([][]string, 0, len(intros))
+	for _, cells := range intros {
+		truncated = append(truncated, truncateCells(cells, widths))
+	}
+	last := len(headers) - 1
+	headers[last] = truncateCells(headers[last], widths)
+	return headers, truncated
+}
+
+// columnWidths determines the width of each column, where outcome columns
+// are at least as wide as the widest outcome symbol.
+func columnWidths(headers, intros [][]string, intro, entryWidth int) []int {
+	var widths []int
+	grow := func(i, n int) {
+		for len(widths) <= i {
+			widths = append(widths, 0)
+		}
+		if n > widths[i] {
+			widths[i] = n
+		}
+	}
+	for _, line := range headers {
+		for i, cell := range line {
+			grow(i, runeLen(cell))
+			if i >= intro {
+				grow(i, entryWidth)
+			}
+		}
+	}
+	for _, cells := range intros {
+		for i, cell := range cells {
+			grow(i, runeLen(cell))
+		}
+	}
+	return widths
+}
+
+func tableWidth(widths []int) int {
+	total := 0
+	for _, w := range widths {
+		total += w + padding
+	}
+	return total - padding
+}
+
+func truncateCells(cells []string, widths []int) []string {
+	truncated := make([]string, 0, len(cells))
+	for i, cell := range cells {
+		if i < len(widths) {
+			cell = middleTruncate(cell, widths[i])
+		}
+		truncated = append(truncated, cell)
+	}
+	return truncated
+}
+
+// middleTruncate shortens s to the given number of runes by replacing its
+// middle part with an ellipsis.
+func middleTruncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(r[:n])
+	}
+	tail := (n - 1) / 2
+	head := n - 1 - tail
+	return string(r[:head]) + ellipsis + string(r[len(r)-tail:])
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
+
+// page writes the rendered table to out. If out is a terminal and the table
+// does not fit onto the screen, it is shown with the pager from the PAGER
+// environment variable, which defaults to 'less'. Paging is disabled when
+// PAGER is set but empty.
+func page(out io.Writer, rendered *bytes.Buffer) {
+	if _, height := terminalSize(out); height > 0 && bytes.Count(rendered.Bytes(), []byte("\n")) >= height {
+		if pager := pagerCommand(); len(pager) > 0 {
+			err := runPager(pager, out, bytes.NewReader(rendered.Bytes()))
+			if err == nil {
+				return
+			}
+			klog.V(2).Infof("Could not start pager %q: %s", pager[0], err)
+		}
+	}
+	_, _ = out.Write(rendered.Bytes())
+}
+
+// pagerCommand determines the command line of the pager.
+func pagerCommand() []string {
+	pager, ok := lookupEnv("PAGER")
+	if !ok {
+		pager = "less"
+	}
+	return strings.Fields(pager)
+}
+
+// runPagerImpl shows the input with the pager. It only fails if the pager
+// cannot be started. Like git, it configures less to keep colors and to exit
+// if the input fits onto the screen.
+func runPagerImpl(pager []string, out io.Writer, in io.Reader) error {
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = in
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	if _, ok := lookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if err := cmd.Wait(); err != nil {
+		klog.V(2).Infof("Pager exited with error: %s", err)
+	}
+	return nil
+}
+
+// terminalSizeImpl determines the size of the terminal. It returns zeros if
+// the writer is not a terminal.
+func terminalSizeImpl(w io.Writer) (width, height int) {
+	if f, ok := w.(*os.File); ok {
+		if width, height, err := term.GetSize(int(f.Fd())); err == nil {
+			return width, height
+		}
+	}
+	return 0, 0
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleTruncate(t *testing.T) {
+	tests := []struct {
+		input    string
+		n        int
+		expected string
+	}{
+		{input: "pods", n: 12, expected: "pods"},
+		{input: "certificaterequests.cert-manager.io", n: 12, expected: "certif…er.io"},
+		{input: "certificaterequests.cert-manager.io", n: 13, expected: "certif…ger.io"},
+		{input: "pods", n: 1, expected: "p"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%d", test.input, test.n), func(t *testing.T) {
+			assert.Equal(t, test.expected, middleTruncate(test.input, test.n))
+		})
+	}
+}
+
+func TestRenderFitsTerminalWidth(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "CREATE", "DELETECOLLECTION"},
+		Rows: []Row{
+			{Intro: []string{"pods"}, Entries: []Outcome{Up, Down}},
+			{Intro: []string{"certificaterequests.cert-manager.io"}, Entries: []Outcome{Up, Err}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		width    int
+		expected string
+	}{
+		{
+			name:  "table fits",
+			width: 80,
+			expected: `NAME                                 CREATE  DELETECOLLECTION
+pods                                 ✔       ✖
+certificaterequests.cert-manager.io  ✔       ERR
+`,
+		},
+		{
+			name:  "wrap headers",
+			width: 60,
+			expected: `                                             DELETE
+                                             COLLEC
+NAME                                 CREATE  TION
+pods                                 ✔       ✖
+certificaterequests.cert-manager.io  ✔       ERR
+`,
+		},
+		{
+			name:  "wrap headers and truncate names",
+			width: 35,
+			expected: `                             DELETE
+                             COLLEC
+NAME                 CREATE  TION
+pods                 ✔       ✖
+certifica…anager.io  ✔       ERR
+`,
+		},
+		{
+			name:  "truncate names to minimum width",
+			width: 10,
+			expected: `                      DELETE
+                      COLLEC
+NAME          CREATE  TION
+pods          ✔       ✖
+certif…er.io  ✔       ERR
+`,
+		},
+	}
+
+	defer func() { terminalSize = terminalSizeImpl }()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			terminalSize = func(io.Writer) (int, int) { return test.width, 0 }
+
+			buf := &bytes.Buffer{}
+			table.Render(buf, "icon-table")
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	tests := []struct {
+		name       string
+		height     int
+		pager      *string
+		pagerErr   error
+		wantPager  []string
+		wantOutput string
+	}{
+		{
+			name:       "not a terminal",
+			wantOutput: "line 1\nline 2\n",
+		},
+		{
+			name:       "fits onto screen",
+			height:     5,
+			wantOutput: "line 1\nline 2\n",
+		},
+		{
+			name:       "default pager",
+			height:     2,
+			wantPager:  []string{"less"},
+			wantOutput: "paged",
+		},
+		{
+			name:       "custom pager",
+			height:     2,
+			pager:      stringPtr("more -d"),
+			wantPager:  []string{"more", "-d"},
+			wantOutput: "paged",
+		},
+		{
+			name:       "paging disabled",
+			height:     2,
+			pager:      stringPtr(""),
+			wantOutput: "line 1\nline 2\n",
+		},
+		{
+			name:       "pager not found",
+			height:     2,
+			pagerErr:   fmt.Errorf("not found"),
+			wantPager:  []string{"less"},
+			wantOutput: "line 1\nline 2\n",
+		},
+	}
+
+	defer func() {
+		terminalSize = terminalSizeImpl
+		runPager = runPagerImpl
+		lookupEnv = os.LookupEnv
+	}()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			terminalSize = func(io.Writer) (int, int) { return 80, test.height }
+			lookupEnv = func(key string) (string, bool) {
+				if key == "PAGER" && test.pager != nil {
+					return *test.pager, true
+				}
+				return "", false
+			}
+			var gotPager []string
+			runPager = func(pager []string, out io.Writer, _ io.Reader) error {
+				gotPager = pager
+				if test.pagerErr != nil {
+					return test.pagerErr
+				}
+				_, err := io.WriteString(out, "paged")
+				return err
+			}
+
+			out := &bytes.Buffer{}
+			page(out, bytes.NewBufferString("line 1\nline 2\n"))
+			assert.Equal(t, test.wantPager, gotPager)
+			assert.Equal(t, test.wantOutput, out.String())
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
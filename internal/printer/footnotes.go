@@ -0,0 +1,62 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+)
+
+// footnotes numbers the notes of a table in order of appearance. Identical
+// notes share the same number.
+type footnotes struct {
+	notes   []string
+	numbers map[string]int
+}
+
+// add registers the note and returns its number.
+func (f *footnotes) add(note string) int {
+	if f.numbers == nil {
+		f.numbers = make(map[string]int)
+	}
+	if n, ok := f.numbers[note]; ok {
+		return n
+	}
+	f.notes = append(f.notes, note)
+	f.numbers[note] = len(f.notes)
+	return len(f.notes)
+}
+
+// shownNote returns the note of the i-th entry of the row, if it is shown.
+// Notes of errors are always shown, other notes only if footnotes are enabled.
+func (p *Table) shownNote(row Row, i int) string {
+	if (p.Footnotes || row.Entries[i] == Err) && i < len(row.Notes) {
+		return row.Notes[i]
+	}
+	return ""
+}
+
+// render prints all notes, separated from the table by an empty line.
+func (f *footnotes) render(out io.Writer) {
+	if len(f.notes) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	for i, note := range f.notes {
+		fmt.Fprintf(out, "[%d] %s\n", i+1, note)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"html/template"
+	"io"
+	"sort"
+
+	"k8s.io/klog/v2"
+)
+
+// htmlTemplate is a standalone report. All styles and scripts are inlined,
+// so that it can be opened without network access.
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Access matrix</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; }
+thead th { position: sticky; top: 0; background: #eee; }
+td { text-align: center; }
+td.intro { text-align: left; }
+.up { background: #c8f0c8; color: #060; }
+.down { background: #f5c6c6; color: #900; }
+.deny { background: #f5e6a8; color: #850; }
+.err { background: #e8c8f0; color: #606; }
+.cancel { background: #eee; color: #666; }
+.none { background: #f8f8f8; }
+tr.section th { background: #ddd; text-align: left; cursor: pointer; }
+tr.section th::before { content: "\25BE  "; }
+tbody.collapsed tr.section th::before { content: "\25B8  "; }
+tbody.collapsed tr.row { display: none; }
+#filter { margin-bottom: 1em; width: 30em; }
+.legend span { padding: 2px 8px; margin-right: 1em; }
+</style>
+</head>
+<body>
+<input id="filter" type="search" placeholder="Filter rows..." autofocus>
+<table>
+<thead>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+</thead>
+{{- range .Sections}}
+<tbody>
+{{- if .Name}}
+<tr class="section"><th colspan="{{$.Columns}}">{{.Name}} ({{len .Rows}})</th></tr>
+{{- end}}
+{{- range .Rows}}
+<tr class="row">{{range .Intro}}<td class="intro">{{.}}</td>{{end}}{{range .Cells}}<td class="{{.Class}}"{{with .Title}} title="{{.}}"{{end}}>{{.Symbol}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+{{- end}}
+</table>
+{{- if .Legend}}
+<p class="legend">{{range .Legend}}<span class="{{.Class}}">{{.Symbol}} {{.Text}}</span>{{end}}</p>
+{{- end}}
+<script>
+document.querySelectorAll("tr.section").forEach(function (header) {
+  header.addEventListener("click", function () {
+    header.parentElement.classList.toggle("collapsed");
+  });
+});
+document.getElementById("filter").addEventListener("input", function (event) {
+  var query = event.target.value.toLowerCase();
+  document.querySelectorAll("tbody").forEach(function (section) {
+    var visible = 0;
+    section.querySelectorAll("tr.row").forEach(function (row) {
+      var match = row.textContent.toLowerCase().indexOf(query) >= 0;
+      row.hidden = !match;
+      if (match) {
+        visible++;
+      }
+    });
+    section.hidden = visible === 0;
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+type htmlCell struct {
+	Class, Symbol, Text string
+	// Title explains the cell on hover, e.g. the message of a request error.
+	Title string
+}
+
+type htmlRow struct {
+	Intro []string
+	Cells []htmlCell
+}
+
+type htmlSection struct {
+	Name string
+	Rows []htmlRow
+}
+
+// renderHTML prints the table as a self-contained HTML report. Rows are
+// grouped by their section, which can be collapsed in the report.
+func (p *Table) renderHTML(out io.Writer) {
+	theme := p.theme()
+	var names []string
+	sections := make(map[string]*htmlSection)
+	for _, row := range p.Rows {
+		s, ok := sections[row.Section]
+		if !ok {
+			s = &htmlSection{Name: row.Section}
+			sections[row.Section] = s
+			names = append(names, row.Section)
+		}
+		r := htmlRow{Intro: row.Intro}
+		for i, e := range row.Entries {
+			r.Cells = append(r.Cells, htmlCell{Class: htmlClass(e), Symbol: theme[e].Symbol, Title: p.shownNote(row, i)})
+		}
+		s.Rows = append(s.Rows, r)
+	}
+	sort.Strings(names)
+
+	data := struct {
+		Headers  []string
+		Columns  int
+		Sections []*htmlSection
+		Legend   []htmlCell
+	}{
+		Headers: p.Headers,
+		Columns: len(p.Headers),
+	}
+	for _, name := range names {
+		data.Sections = append(data.Sections, sections[name])
+	}
+	for _, o := range outcomeOrder {
+		if text, ok := p.Legend[o]; ok {
+			data.Legend = append(data.Legend, htmlCell{Class: htmlClass(o), Symbol: theme[o].Symbol, Text: text})
+		}
+	}
+
+	if err := htmlTemplate.Execute(out, data); err != nil {
+		klog.Errorf("Could not render html report: %s", err)
+	}
+}
+
+func htmlClass(o Outcome) string {
+	switch o {
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	case Deny:
+		return "deny"
+	case Err:
+		return "err"
+	case Cancel:
+		return "cancel"
+	default:
+		return "none"
+	}
+}
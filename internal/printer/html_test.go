@@ -0,0 +1,82 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderHTML(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "GET", "LIST"},
+		Rows: []Row{
+			{Intro: []string{"pods"}, Entries: []Outcome{Up, Down}, Section: "core"},
+			{Intro: []string{"deployments.apps"}, Entries: []Outcome{None, Err}, Section: "apps"},
+			{Intro: []string{"<script>"}, Entries: []Outcome{Up, Up}, Section: "core"},
+		},
+		Legend: map[Outcome]string{Up: "allowed"},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "html")
+	html := buf.String()
+
+	assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	assert.NotContains(t, html, "http")
+	assert.Contains(t, html, `<tr><th>NAME</th><th>GET</th><th>LIST</th></tr>`)
+	assert.Contains(t, html, `<tr class="row"><td class="intro">pods</td><td class="up">✔</td><td class="down">✖</td></tr>`)
+	assert.Contains(t, html, `<td class="none"></td><td class="err">ERR</td>`)
+	assert.Contains(t, html, `<td class="intro">&lt;script&gt;</td>`)
+	assert.Contains(t, html, `<span class="up">✔ allowed</span>`)
+
+	apps := strings.Index(html, `<th colspan="3">apps (1)</th>`)
+	core := strings.Index(html, `<th colspan="3">core (2)</th>`)
+	assert.True(t, apps > 0 && core > apps, "sections are sorted by name")
+}
+
+func TestRenderHTML_Theme(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "GET"},
+		Rows:    []Row{{Intro: []string{"pods"}, Entries: []Outcome{Up}}},
+		Legend:  map[Outcome]string{Up: "allowed"},
+		Theme:   Theme{Up: {Symbol: "Y"}},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "html")
+
+	assert.Contains(t, buf.String(), `<td class="up">Y</td>`)
+	assert.Contains(t, buf.String(), `<span class="up">Y allowed</span>`)
+}
+
+func TestRenderHTML_Notes(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "GET", "LIST"},
+		Rows: []Row{
+			{Intro: []string{"metrics"}, Entries: []Outcome{Up, Err}, Notes: []string{"allowed by view", "forbidden: <denied>"}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "html")
+
+	assert.Contains(t, buf.String(), `<td class="up">✔</td><td class="err" title="forbidden: &lt;denied&gt;">ERR</td>`)
+}
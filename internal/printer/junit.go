@@ -0,0 +1,171 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"k8s.io/klog/v2"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// renderJUnit prints the table as a JUnit XML report. For an access matrix,
+// every cell is a test case, where request errors are reported as errors and
+// entries with failures as failures. For a diff, every row is a failing test
+// case, because it shows unexpected changes of the access rights. A diff
+// without rows is a single passing test case.
+func (p *Table) renderJUnit(out io.Writer) {
+	suite := junitTestSuite{Name: constants.CommandName}
+	if p.Diff {
+		suite.Name += " diff"
+	}
+
+	intro := p.introColumns()
+	verbs := make([]string, 0, len(p.Headers))
+	for _, h := range p.Headers[intro:] {
+		verbs = append(verbs, strings.ToLower(h))
+	}
+
+	for _, row := range p.Rows {
+		name := rowName(row)
+		if p.Diff {
+			suite.TestCases = append(suite.TestCases, p.junitDiffCase(name, verbs, row))
+			continue
+		}
+		for i, e := range row.Entries {
+			tc := junitTestCase{ClassName: name, Name: verbs[i]}
+			if i < len(row.Failures) && row.Failures[i] != "" {
+				tc.Failure = &junitMessage{Message: row.Failures[i]}
+				suite.TestCases = append(suite.TestCases, tc)
+				continue
+			}
+			switch e {
+			case Up, Down, Deny:
+				tc.SystemOut = p.Legend[e]
+			case None, Cancel:
+				tc.Skipped = &junitMessage{Message: p.Legend[e]}
+			case Err:
+				tc.Error = &junitMessage{Message: p.Legend[e]}
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+	}
+
+	if p.Diff && len(p.Rows) == 0 {
+		// report the successful comparison, CI systems treat empty reports as errors
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			ClassName: constants.CommandName,
+			Name:      "access rights",
+			SystemOut: p.Legend[None],
+		})
+	}
+
+	for _, tc := range suite.TestCases {
+		switch {
+		case tc.Failure != nil:
+			suite.Failures++
+		case tc.Error != nil:
+			suite.Errors++
+		case tc.Skipped != nil:
+			suite.Skipped++
+		}
+	}
+	suite.Tests = len(suite.TestCases)
+
+	report := junitTestSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	fmt.Fprint(out, xml.Header)
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		klog.Errorf("Could not render junit report: %s", err)
+		return
+	}
+	fmt.Fprintln(out)
+}
+
+// junitDiffCase creates a failing test case which lists the verbs with
+// gained or lost access.
+func (p *Table) junitDiffCase(name string, verbs []string, row Row) junitTestCase {
+	changed := make(map[Outcome][]string)
+	for i, e := range row.Entries {
+		changed[e] = append(changed[e], verbs[i])
+	}
+
+	var msgs []string
+	for _, o := range []Outcome{Up, Down} {
+		if vs, ok := changed[o]; ok {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", p.Legend[o], strings.Join(vs, ", ")))
+		}
+	}
+	return junitTestCase{
+		ClassName: constants.CommandName,
+		Name:      name,
+		Failure:   &junitMessage{Message: strings.Join(msgs, "; ")},
+	}
+}
+
+// rowName joins the non-empty intro columns of the row.
+func rowName(row Row) string {
+	parts := make([]string, 0, len(row.Intro))
+	for _, s := range row.Intro {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderJUnit(t *testing.T) {
+	tests := []struct {
+		name  string
+		table *Table
+		want  string
+	}{
+		{
+			name: "access matrix",
+			table: &Table{
+				Headers: []string{"NAME", "GET", "LIST"},
+				Rows: []Row{
+					{Intro: []string{"configmaps"}, Entries: []Outcome{Up, Down}},
+					{Intro: []string{"deployments.apps"}, Entries: []Outcome{None, Err}},
+				},
+				Legend: map[Outcome]string{Up: "allowed", Down: "denied", None: "not applicable", Err: "request error"},
+			},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="rakkess" tests="4" failures="0" errors="1">
+  <testsuite name="rakkess" tests="4" failures="0" errors="1" skipped="1">
+    <testcase classname="configmaps" name="get">
+      <system-out>allowed</system-out>
+    </testcase>
+    <testcase classname="configmaps" name="list">
+      <system-out>denied</system-out>
+    </testcase>
+    <testcase classname="deployments.apps" name="get">
+      <skipped message="not applicable"></skipped>
+    </testcase>
+    <testcase classname="deployments.apps" name="list">
+      <error message="request error"></error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name: "diff",
+			table: &Table{
+				Headers: []string{"NAME", "GET", "LIST", "CREATE"},
+				Rows: []Row{
+					{Intro: []string{"configmaps"}, Entries: []Outcome{Up, Down, Up}},
+				},
+				Legend: map[Outcome]string{Up: "gained", Down: "lost"},
+				Diff:   true,
+			},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="rakkess diff" tests="1" failures="1" errors="0">
+  <testsuite name="rakkess diff" tests="1" failures="1" errors="0" skipped="0">
+    <testcase classname="rakkess" name="configmaps">
+      <failure message="gained: get, create; lost: list"></failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name: "diff without changes",
+			table: &Table{
+				Headers: []string{"NAME", "GET"},
+				Legend:  map[Outcome]string{Up: "gained", Down: "lost", None: "unchanged"},
+				Diff:    true,
+			},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="rakkess diff" tests="1" failures="0" errors="0">
+  <testsuite name="rakkess diff" tests="1" failures="0" errors="0" skipped="0">
+    <testcase classname="rakkess" name="access rights">
+      <system-out>unchanged</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name: "access matrix with failures",
+			table: &Table{
+				Headers: []string{"NAME", "GET", "LIST"},
+				Rows: []Row{
+					{Intro: []string{"secrets"}, Entries: []Outcome{Up, Down}, Failures: []string{"expected denied, but allowed", ""}},
+				},
+				Legend: map[Outcome]string{Up: "allowed", Down: "denied"},
+			},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="rakkess" tests="2" failures="1" errors="0">
+  <testsuite name="rakkess" tests="2" failures="1" errors="0" skipped="0">
+    <testcase classname="secrets" name="get">
+      <failure message="expected denied, but allowed"></failure>
+    </testcase>
+    <testcase classname="secrets" name="list">
+      <system-out>denied</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			test.table.Render(buf, "junit")
+			want := strings.ReplaceAll(test.want, "rakkess", constants.CommandName)
+			assert.Equal(t, want, buf.String())
+		})
+	}
+}
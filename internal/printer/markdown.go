@@ -0,0 +1,93 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+var markdownEscaper = strings.NewReplacer("|", `\|`)
+
+// renderMarkdown prints the table as GitHub-flavored markdown, followed by
+// the notes and the legend if there are any.
+func (p *Table) renderMarkdown(out io.Writer) {
+	intro := p.introColumns()
+
+	// left-align the intro and center the outcomes
+	var align []string
+	for i := range p.Headers {
+		if i < intro {
+			align = append(align, "---")
+		} else {
+			align = append(align, ":-:")
+		}
+	}
+
+	theme := p.theme()
+	writeMarkdownRow(out, p.Headers)
+	writeMarkdownRow(out, align)
+	var notes footnotes
+	for _, row := range p.Rows {
+		cells := append([]string{}, row.Intro...)
+		for i, e := range row.Entries {
+			cell := theme[e].Symbol
+			if note := p.shownNote(row, i); note != "" {
+				cell += fmt.Sprintf(" [%d]", notes.add(note))
+			}
+			cells = append(cells, cell)
+		}
+		writeMarkdownRow(out, cells)
+	}
+
+	if len(notes.notes) > 0 {
+		fmt.Fprintln(out, "\n**Notes:**")
+		fmt.Fprintln(out)
+		for i, note := range notes.notes {
+			fmt.Fprintf(out, "%d. %s\n", i+1, note)
+		}
+	}
+
+	if len(p.Legend) == 0 {
+		return
+	}
+	var legend []string
+	for _, o := range outcomeOrder {
+		if text, ok := p.Legend[o]; ok {
+			legend = append(legend, fmt.Sprintf("%s %s", markdownSymbol(theme, o), text))
+		}
+	}
+	fmt.Fprintf(out, "\n**Legend:** %s\n", strings.Join(legend, ", "))
+}
+
+func writeMarkdownRow(out io.Writer, cells []string) {
+	escaped := make([]string, 0, len(cells))
+	for _, c := range cells {
+		escaped = append(escaped, markdownEscaper.Replace(c))
+	}
+	fmt.Fprintf(out, "| %s |\n", strings.Join(escaped, " | "))
+}
+
+// markdownSymbol returns the cell content of the outcome, so that empty cells
+// can be referred to in the legend.
+func markdownSymbol(theme Theme, o Outcome) string {
+	if theme[o].Symbol == "" {
+		return "(empty)"
+	}
+	return theme[o].Symbol
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		table *Table
+		want  string
+	}{
+		{
+			name: "without legend",
+			table: &Table{
+				Headers: []string{"NAME", "GET", "LIST"},
+				Rows: []Row{
+					{Intro: []string{"configmaps"}, Entries: []Outcome{Up, Down}},
+					{Intro: []string{"deployments.apps"}, Entries: []Outcome{None, Err}},
+				},
+			},
+			want: `| NAME | GET | LIST |
+| --- | :-: | :-: |
+| configmaps | ✔ | ✖ |
+| deployments.apps |  | ERR |
+`,
+		},
+		{
+			name: "with legend",
+			table: &Table{
+				Headers: []string{"NAME", "KIND", "GET"},
+				Rows: []Row{
+					{Intro: []string{"a|b", "User"}, Entries: []Outcome{Up}},
+				},
+				Legend: map[Outcome]string{None: "unchanged", Down: "lost", Up: "gained"},
+			},
+			want: `| NAME | KIND | GET |
+| --- | --- | :-: |
+| a\|b | User | ✔ |
+
+**Legend:** ✔ gained, ✖ lost, (empty) unchanged
+`,
+		},
+		{
+			name: "with theme",
+			table: &Table{
+				Headers: []string{"NAME", "GET", "LIST"},
+				Rows: []Row{
+					{Intro: []string{"configmaps"}, Entries: []Outcome{Up, Down}},
+				},
+				Legend: map[Outcome]string{Up: "allowed", Down: "denied"},
+				Theme:  Theme{Up: {Symbol: "Y"}, Down: {Symbol: "N"}},
+			},
+			want: `| NAME | GET | LIST |
+| --- | :-: | :-: |
+| configmaps | Y | N |
+
+**Legend:** Y allowed, N denied
+`,
+		},
+		{
+			name: "with error notes",
+			table: &Table{
+				Headers: []string{"NAME", "GET", "LIST"},
+				Rows: []Row{
+					{Intro: []string{"configmaps"}, Entries: []Outcome{Up, Up}, Notes: []string{"allowed by view", "allowed by view"}},
+					{Intro: []string{"metrics"}, Entries: []Outcome{Err, Err}, Notes: []string{"timeout: context deadline exceeded", "timeout: context deadline exceeded"}},
+				},
+			},
+			want: `| NAME | GET | LIST |
+| --- | :-: | :-: |
+| configmaps | ✔ | ✔ |
+| metrics | ERR [1] | ERR [1] |
+
+**Notes:**
+
+1. timeout: context deadline exceeded
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			test.table.Render(buf, "markdown")
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
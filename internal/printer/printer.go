@@ -17,6 +17,8 @@ limitations under the License.
 package printer
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
 	"io"
 	"strings"
@@ -30,6 +32,7 @@ type color int
 const (
 	red    = color(31)
 	green  = color(32)
+	yellow = color(33)
 	purple = color(35)
 	none   = color(0)
 )
@@ -46,15 +49,51 @@ const (
 	Up
 	Down
 	Err
+	// Deny is an explicit denial, as opposed to Down where no rule allowed
+	// the access.
+	Deny
+	// Cancel marks entries which were not checked, because the review was
+	// interrupted.
+	Cancel
 )
 
+// outcomeOrder is the order in which outcomes are explained in legends.
+var outcomeOrder = []Outcome{Up, Down, Deny, Err, Cancel, None}
+
 type Row struct {
 	Intro   []string
 	Entries []Outcome
+	// Section optionally assigns the row to a group of related rows.
+	Section string
+	// Notes optionally explains each entry. Notes are only shown as
+	// footnotes, if the table is configured to do so. Notes of errors are
+	// always shown, because they are the only hint at the cause.
+	Notes []string
+	// Failures optionally marks entries which deviate from the expected
+	// access rights, with an explanation. They fail the junit report.
+	Failures []string
 }
 type Table struct {
 	Headers []string
 	Rows    []Row
+	// Legend explains the meaning of the outcomes. It is optional and only
+	// shown by output formats which are pasted into documents.
+	Legend map[Outcome]string
+	// Diff marks a table which shows changes of access rights instead of
+	// the access rights themselves.
+	Diff bool
+	// RotateHeaders prints the headers of the outcome columns vertically, so
+	// that the columns become narrow.
+	RotateHeaders bool
+	// Grouped prints a header line before the rows of each section. The
+	// rows of a section need to be adjacent, see GroupBySection.
+	Grouped bool
+	// Footnotes marks entries with notes and prints the notes after the table.
+	Footnotes bool
+	// Color is one of 'auto' (default), 'always', or 'never'.
+	Color string
+	// Theme optionally overrides the symbols and colors of the icon table.
+	Theme Theme
 }
 
 func TableWithHeaders(headers []string) *Table {
@@ -71,67 +110,212 @@ func (p *Table) AddRow(intro []string, outcomes ...Outcome) {
 	p.Rows = append(p.Rows, row)
 }
 
+// AddRowInSection is like AddRow, but assigns the row to the given section.
+func (p *Table) AddRowInSection(section string, intro []string, outcomes ...Outcome) {
+	p.AddRow(intro, outcomes...)
+	p.Rows[len(p.Rows)-1].Section = section
+}
+
+// IsHumanReadable checks if the output format is only meant to be read by
+// humans, so that additional hints may be mixed into the output.
+func IsHumanReadable(outputFormat string) bool {
+	return outputFormat == "icon-table" || outputFormat == "ascii-table" || outputFormat == "wide"
+}
+
+// IsTable checks if the output format is rendered from a Table.
+func IsTable(outputFormat string) bool {
+	switch outputFormat {
+	case "icon-table", "ascii-table", "wide", "compact", "csv", "tsv", "markdown", "html", "junit":
+		return true
+	}
+	return false
+}
+
+// IsDelimited checks if the output format prints delimiter-separated values.
+func IsDelimited(outputFormat string) bool {
+	return outputFormat == "csv" || outputFormat == "tsv"
+}
+
 func (p *Table) Render(out io.Writer, outputFormat string) {
+	switch outputFormat {
+	case "csv":
+		p.renderDelimited(out, ',')
+		return
+	case "tsv":
+		p.renderDelimited(out, '\t')
+		return
+	case "markdown":
+		p.renderMarkdown(out)
+		return
+	case "html":
+		p.renderHTML(out)
+		return
+	case "junit":
+		p.renderJUnit(out)
+		return
+	case "compact":
+		p.renderCompact(out)
+		return
+	}
+
 	once.Do(func() { initTerminal(out) })
 
-	conv := humanreadableAccessCode
-	if isTerminal(out) {
-		conv = colored(conv)
-	}
+	theme := p.theme()
+	symbol := func(o Outcome) string { return theme[o].Symbol }
 	if outputFormat == "ascii-table" {
-		conv = asciiAccessCode
+		symbol = asciiAccessCode
+	}
+	conv := symbol
+	if useColor(p.Color, out) && outputFormat != "ascii-table" {
+		conv = colored(symbol, theme)
 	}
 
-	w := tabwriter.NewWriter(out, 4, 8, 2, ' ', tabwriter.SmashEscape|tabwriter.StripEscape)
-	defer w.Flush()
+	headers, intros := p.headerLines(), p.intros()
+	if width, _ := terminalSize(out); width > 0 {
+		headers, intros = p.fit(width, headers, intros, symbol)
+	}
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 4, 8, 2, ' ', tabwriter.SmashEscape|tabwriter.StripEscape)
+	var notes footnotes
 
 	// table header
-	for i, h := range p.Headers {
-		if i == 0 {
-			fmt.Fprint(w, h)
-		} else {
-			fmt.Fprintf(w, "\t%s", h)
-		}
+	for _, line := range headers {
+		fmt.Fprintf(w, "%s\n", strings.Join(line, "\t"))
 	}
-	fmt.Fprint(w, "\n")
 
 	// table body
-	for _, row := range p.Rows {
-		fmt.Fprintf(w, "%s", strings.Join(row.Intro, "\t"))
-		for _, e := range row.Entries {
+	for i, row := range p.Rows {
+		if p.Grouped && (i == 0 || p.Rows[i-1].Section != row.Section) {
+			fmt.Fprint(w, p.sectionHeader(row.Section))
+		}
+		fmt.Fprintf(w, "%s", strings.Join(intros[i], "\t"))
+		for i, e := range row.Entries {
 			fmt.Fprintf(w, "\t%s", conv(e)) // FIXME
+			if note := p.shownNote(row, i); note != "" {
+				fmt.Fprintf(w, " [%d]", notes.add(note))
+			}
 		}
 		fmt.Fprint(w, "\n")
 	}
+	w.Flush()
+
+	notes.render(buf)
+	page(out, buf)
 }
 
-func humanreadableAccessCode(o Outcome) string {
-	switch o {
-	case None:
-		return ""
-	case Up:
-		return "✔" // ✓
-	case Down:
-		return "✖" // ✕
-	case Err:
-		return "ERR"
-	default:
-		panic("unknown access code")
+// renderDelimited prints the table as delimiter-separated values with a
+// header row. It never emits escape sequences, so that the output can be
+// loaded into spreadsheets. If any notes are shown, they are collected in an
+// additional NOTES column, e.g. 'LIST: timeout: context deadline exceeded'.
+func (p *Table) renderDelimited(out io.Writer, delimiter rune) {
+	w := csv.NewWriter(out)
+	w.Comma = delimiter
+	defer w.Flush()
+
+	intro := p.introColumns()
+	rowNotes := make([]string, 0, len(p.Rows))
+	withNotes := false
+	for _, row := range p.Rows {
+		var notes []string
+		for i := range row.Entries {
+			if note := p.shownNote(row, i); note != "" {
+				notes = append(notes, fmt.Sprintf("%s: %s", p.Headers[intro+i], note))
+			}
+		}
+		rowNotes = append(rowNotes, strings.Join(notes, "; "))
+		withNotes = withNotes || len(notes) > 0
+	}
+
+	headers := p.Headers
+	if withNotes {
+		headers = append(append([]string{}, headers...), "NOTES")
+	}
+	_ = w.Write(headers)
+	for r, row := range p.Rows {
+		record := make([]string, 0, len(row.Intro)+len(row.Entries)+1)
+		record = append(record, row.Intro...)
+		for _, e := range row.Entries {
+			record = append(record, asciiAccessCode(e))
+		}
+		if withNotes {
+			record = append(record, rowNotes[r])
+		}
+		_ = w.Write(record)
 	}
 }
 
-func colored(wrap func(Outcome) string) func(Outcome) string {
-	return func(o Outcome) string {
-		c := none
-		switch o {
-		case Up:
-			c = green
-		case Down:
-			c = red
-		case Err:
-			c = purple
+// introColumns determines the number of leading columns which do not hold outcomes.
+func (p *Table) introColumns() int {
+	if len(p.Rows) == 0 {
+		return len(p.Headers)
+	}
+	return len(p.Rows[0].Intro)
+}
+
+// headerLines returns the cells of each line of the table header.
+func (p *Table) headerLines() [][]string {
+	if p.RotateHeaders {
+		return p.wrappedHeaders(1)
+	}
+	return [][]string{p.Headers}
+}
+
+// intros returns the intro cells of each row.
+func (p *Table) intros() [][]string {
+	intros := make([][]string, 0, len(p.Rows))
+	for _, row := range p.Rows {
+		intros = append(intros, row.Intro)
+	}
+	return intros
+}
+
+// wrappedHeaders splits the headers of the outcome columns into chunks of the
+// given size. The chunks are bottom-aligned, so that the last chunk is right
+// above the column. With a size of one, the headers are rotated.
+func (p *Table) wrappedHeaders(size int) [][]string {
+	intro := p.introColumns()
+	var wrapped [][]string
+	height := 1
+	for _, h := range p.Headers[intro:] {
+		r := []rune(h)
+		var chunks []string
+		for len(r) > size {
+			chunks = append(chunks, string(r[:size]))
+			r = r[size:]
 		}
-		return fmt.Sprintf("\xff\033[%dm\xff%s\xff\033[0m\xff", c, wrap(o))
+		chunks = append(chunks, string(r))
+		wrapped = append(wrapped, chunks)
+		if len(chunks) > height {
+			height = len(chunks)
+		}
+	}
+
+	lines := make([][]string, 0, height)
+	for line := 0; line < height; line++ {
+		cells := make([]string, 0, len(p.Headers))
+		for i := 0; i < intro; i++ {
+			if line == height-1 {
+				cells = append(cells, p.Headers[i])
+			} else {
+				cells = append(cells, "")
+			}
+		}
+		for _, chunks := range wrapped {
+			if offset := line - (height - len(chunks)); offset >= 0 {
+				cells = append(cells, chunks[offset])
+			} else {
+				cells = append(cells, "")
+			}
+		}
+		lines = append(lines, cells)
+	}
+	return lines
+}
+
+func colored(wrap func(Outcome) string, theme Theme) func(Outcome) string {
+	return func(o Outcome) string {
+		return fmt.Sprintf("\xff\033[%dm\xff%s\xff\033[0m\xff", theme[o].Color, wrap(o))
 	}
 }
 
@@ -143,8 +327,12 @@ func asciiAccessCode(o Outcome) string {
 		return "yes"
 	case Down:
 		return "no"
+	case Deny:
+		return "deny"
 	case Err:
 		return "ERR"
+	case Cancel:
+		return "cancelled"
 	default:
 		panic("unknown access code")
 	}
@@ -82,6 +82,18 @@ func TestPrintResults(t *testing.T) {
 			HEADER + "resource1  \033[35mERR\033[0m  \033[35mERR\033[0m\n",
 			HEADER + "resource1  ERR  ERR\n",
 		},
+		{
+			"single result, all explicitly denied",
+			&Table{
+				Headers: []string{"NAME", "GET", "LIST"},
+				Rows: []Row{
+					{Intro: []string{"resource1"}, Entries: []Outcome{Deny, Deny}},
+				},
+			},
+			HEADER + "resource1  ⊘    ⊘\n",
+			HEADER + "resource1  \033[33m⊘\033[0m    \033[33m⊘\033[0m\n",
+			"NAME       GET   LIST\nresource1  deny  deny\n",
+		},
 		{
 			"single result, mixed",
 			&Table{
@@ -122,7 +134,7 @@ func TestPrintResults(t *testing.T) {
 		})
 	}
 
-	for _, tc := range tests[0:4] {
+	for _, tc := range tests[0:5] {
 		isTerminal = func(w io.Writer) bool {
 			return true
 		}
@@ -141,3 +153,87 @@ func TestPrintResults(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderDelimited(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "GROUP", "NAMESPACE", "GET", "LIST"},
+		Rows: []Row{
+			{Intro: []string{"configmaps", "", "default"}, Entries: []Outcome{Up, Down}},
+			{Intro: []string{"deployments", "apps", "default"}, Entries: []Outcome{None, Err}},
+		},
+	}
+
+	isTerminal = func(w io.Writer) bool {
+		return true
+	}
+	defer func() {
+		isTerminal = isTerminalImpl
+	}()
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "csv")
+	assert.Equal(t, "NAME,GROUP,NAMESPACE,GET,LIST\nconfigmaps,,default,yes,no\ndeployments,apps,default,n/a,ERR\n", buf.String())
+
+	buf = &bytes.Buffer{}
+	table.Render(buf, "tsv")
+	assert.Equal(t, "NAME\tGROUP\tNAMESPACE\tGET\tLIST\nconfigmaps\t\tdefault\tyes\tno\ndeployments\tapps\tdefault\tn/a\tERR\n", buf.String())
+}
+
+func TestRenderFootnotes(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "GET", "LIST"},
+		Rows: []Row{
+			{Intro: []string{"configmaps"}, Entries: []Outcome{Up, Up}, Notes: []string{"allowed by view", "allowed by view"}},
+			{Intro: []string{"secrets"}, Entries: []Outcome{Down, Up}, Notes: []string{"", "allowed by admin"}},
+			{Intro: []string{"pods"}, Entries: []Outcome{Up, None}},
+		},
+		Footnotes: true,
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "ascii-table")
+	assert.Equal(t, `NAME        GET      LIST
+configmaps  yes [1]  yes [1]
+secrets     no       yes [2]
+pods        yes      n/a
+
+[1] allowed by view
+[2] allowed by admin
+`, buf.String())
+}
+
+func TestRenderFootnotes_Errors(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "GET", "LIST"},
+		Rows: []Row{
+			{Intro: []string{"configmaps"}, Entries: []Outcome{Up, Up}, Notes: []string{"allowed by view", "allowed by view"}},
+			{Intro: []string{"metrics"}, Entries: []Outcome{Err, Err}, Notes: []string{"timeout: context deadline exceeded", "timeout: context deadline exceeded"}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "ascii-table")
+	assert.Equal(t, `NAME        GET      LIST
+configmaps  yes      yes
+metrics     ERR [1]  ERR [1]
+
+[1] timeout: context deadline exceeded
+`, buf.String())
+}
+
+func TestRenderDelimited_Notes(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "GET", "LIST"},
+		Rows: []Row{
+			{Intro: []string{"configmaps"}, Entries: []Outcome{Up, Up}, Notes: []string{"allowed by view", "allowed by view"}},
+			{Intro: []string{"metrics"}, Entries: []Outcome{Err, Err}, Notes: []string{"timeout: context deadline exceeded", "forbidden"}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "csv")
+	assert.Equal(t, `NAME,GET,LIST,NOTES
+configmaps,yes,yes,
+metrics,ERR,ERR,GET: timeout: context deadline exceeded; LIST: forbidden
+`, buf.String())
+}
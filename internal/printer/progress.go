@@ -0,0 +1,62 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+)
+
+// clearLine moves the cursor to the start of the line and erases the line.
+const clearLine = "\r\033[K"
+
+// Progress shows how many resources have been checked so far. It only prints
+// anything if the output is a terminal, so that logs are not cluttered.
+type Progress struct {
+	out     io.Writer
+	enabled bool
+	total   int
+	checked int
+	errors  int
+}
+
+// NewProgress creates a Progress for the given number of resources.
+func NewProgress(out io.Writer, total int) *Progress {
+	return &Progress{
+		out:     out,
+		enabled: isTerminal(out),
+		total:   total,
+	}
+}
+
+// Add counts a checked resource and updates the progress line.
+func (p *Progress) Add(failed bool) {
+	p.checked++
+	if failed {
+		p.errors++
+	}
+	if p.enabled {
+		fmt.Fprintf(p.out, "%sChecked %d/%d resources, %d errors", clearLine, p.checked, p.total, p.errors)
+	}
+}
+
+// Clear removes the progress line, so that other output can be printed.
+func (p *Progress) Clear() {
+	if p.enabled && p.checked > 0 {
+		fmt.Fprint(p.out, clearLine)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	defer func() { isTerminal = isTerminalImpl }()
+
+	isTerminal = func(io.Writer) bool { return true }
+	buf := &bytes.Buffer{}
+	p := NewProgress(buf, 3)
+	p.Add(false)
+	p.Add(true)
+	p.Clear()
+	assert.Equal(t, "\r\033[KChecked 1/3 resources, 0 errors\r\033[KChecked 2/3 resources, 1 errors\r\033[K", buf.String())
+
+	isTerminal = func(io.Writer) bool { return false }
+	buf = &bytes.Buffer{}
+	p = NewProgress(buf, 3)
+	p.Add(false)
+	p.Clear()
+	assert.Empty(t, buf.String())
+}
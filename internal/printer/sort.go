@@ -0,0 +1,84 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortBy orders the rows by the given key, which is one of 'name', 'group',
+// 'allowed-count', or 'denied-count'. Rows with equal keys are ordered by name.
+func (p *Table) SortBy(key string) {
+	var less func(a, b Row) bool
+	switch key {
+	case "name":
+		less = func(a, b Row) bool { return false }
+	case "group":
+		less = func(a, b Row) bool { return a.Section < b.Section }
+	case "allowed-count":
+		less = func(a, b Row) bool { return count(a, Up) > count(b, Up) }
+	case "denied-count":
+		less = func(a, b Row) bool { return count(a, Down)+count(a, Deny) > count(b, Down)+count(b, Deny) }
+	default:
+		panic(fmt.Sprintf("unknown sort key %q", key))
+	}
+
+	sort.SliceStable(p.Rows, func(i, j int) bool {
+		a, b := p.Rows[i], p.Rows[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return rowName(a) < rowName(b)
+	})
+}
+
+// GroupBySection moves rows of the same section next to each other and marks
+// the table as grouped, so that a header is printed for each section. The
+// order of the sections is given by less, and the order of rows within a
+// section is retained.
+func (p *Table) GroupBySection(less func(a, b string) bool) {
+	sort.SliceStable(p.Rows, func(i, j int) bool {
+		return less(p.Rows[i].Section, p.Rows[j].Section)
+	})
+	p.Grouped = true
+}
+
+// count determines how often the given outcome occurs in the row.
+func count(row Row, o Outcome) int {
+	n := 0
+	for _, e := range row.Entries {
+		if e == o {
+			n++
+		}
+	}
+	return n
+}
+
+// sectionHeader is a table line which introduces the rows of a section. The
+// section name is escaped, so that it does not widen the first column.
+func (p *Table) sectionHeader(section string) string {
+	tabs := len(p.Headers) - 1
+	if tabs < 0 {
+		tabs = 0
+	}
+	return fmt.Sprintf("\xff%s:\xff%s\n", section, strings.Repeat("\t", tabs))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sortTestTable() *Table {
+	return &Table{
+		Headers: []string{"NAME", "LIST", "CREATE"},
+		Rows: []Row{
+			{Intro: []string{"pods"}, Entries: []Outcome{Up, Down}, Section: "core"},
+			{Intro: []string{"deployments.apps"}, Entries: []Outcome{Up, Up}, Section: "apps"},
+			{Intro: []string{"configmaps"}, Entries: []Outcome{Down, Down}, Section: "core"},
+		},
+	}
+}
+
+func names(t *Table) []string {
+	var result []string
+	for _, row := range t.Rows {
+		result = append(result, rowName(row))
+	}
+	return result
+}
+
+func TestTable_SortBy(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected []string
+	}{
+		{
+			key:      "name",
+			expected: []string{"configmaps", "deployments.apps", "pods"},
+		},
+		{
+			key:      "group",
+			expected: []string{"deployments.apps", "configmaps", "pods"},
+		},
+		{
+			key:      "allowed-count",
+			expected: []string{"deployments.apps", "pods", "configmaps"},
+		},
+		{
+			key:      "denied-count",
+			expected: []string{"configmaps", "pods", "deployments.apps"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			table := sortTestTable()
+			table.SortBy(test.key)
+			assert.Equal(t, test.expected, names(table))
+		})
+	}
+}
+
+func TestTable_GroupBySection(t *testing.T) {
+	table := sortTestTable()
+	table.GroupBySection(func(a, b string) bool { return a > b })
+
+	assert.Equal(t, []string{"pods", "configmaps", "deployments.apps"}, names(table))
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "ascii-table")
+	assert.Equal(t, "NAME              LIST  CREATE\n"+
+		"core:                        \n"+
+		"pods              yes   no\n"+
+		"configmaps        no    no\n"+
+		"apps:                        \n"+
+		"deployments.apps  yes   yes\n", buf.String())
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/yaml"
+)
+
+// IsStructured checks if the output format is a machine-readable document
+// which is rendered from the full result instead of a Table.
+func IsStructured(outputFormat string) bool {
+	return outputFormat == "json" || outputFormat == "yaml" || IsTemplate(outputFormat)
+}
+
+// IsTemplate checks if the output format is a template such as
+// 'jsonpath={.status}'.
+func IsTemplate(outputFormat string) bool {
+	for _, t := range constants.ValidTemplateFormats {
+		if strings.HasPrefix(outputFormat, t+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintObject renders obj in the given machine-readable output format.
+func PrintObject(out io.Writer, outputFormat string, obj runtime.Object) error {
+	if IsTemplate(outputFormat) {
+		return printTemplate(out, outputFormat, obj)
+	}
+
+	switch outputFormat {
+	case "json":
+		data, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	case "yaml":
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	default:
+		return fmt.Errorf("unexpected output format: %s", outputFormat)
+	}
+}
+
+// printTemplate renders obj with the go-template and jsonpath printers known
+// from kubectl.
+func printTemplate(out io.Writer, outputFormat string, obj runtime.Object) error {
+	var flags interface {
+		ToPrinter(string) (printers.ResourcePrinter, error)
+	}
+	if strings.HasPrefix(outputFormat, "jsonpath") {
+		flags = genericclioptions.NewJSONPathPrintFlags("", true)
+	} else {
+		flags = genericclioptions.NewGoTemplatePrintFlags()
+	}
+
+	p, err := flags.ToPrinter(outputFormat)
+	if err != nil {
+		return err
+	}
+	return p.PrintObj(obj, out)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPrintObject(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":  "AccessMatrix",
+		"verbs": []interface{}{"get"},
+	}}
+
+	tests := []struct {
+		name        string
+		format      string
+		want        string
+		expectedErr string
+	}{
+		{
+			name:   "json",
+			format: "json",
+			want:   "{\n  \"kind\": \"AccessMatrix\",\n  \"verbs\": [\n    \"get\"\n  ]\n}\n",
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			want:   "kind: AccessMatrix\nverbs:\n- get\n",
+		},
+		{
+			name:   "go-template",
+			format: "go-template={{range .verbs}}{{.}}{{end}}",
+			want:   "get",
+		},
+		{
+			name:   "jsonpath",
+			format: "jsonpath={.kind}",
+			want:   "AccessMatrix",
+		},
+		{
+			name:        "go-template-file without file",
+			format:      "go-template-file=does-not-exist",
+			expectedErr: "error reading --template does-not-exist, open does-not-exist: no such file or directory",
+		},
+		{
+			name:        "table format",
+			format:      "icon-table",
+			expectedErr: "unexpected output format: icon-table",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := PrintObject(buf, test.format, obj)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
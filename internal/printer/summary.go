@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/corneliusweig/tabwriter"
+)
+
+// Summary tallies the outcomes of an access matrix per verb and overall.
+type Summary struct {
+	Verbs []string
+	// Counts holds the number of each outcome per verb.
+	Counts map[string]map[Outcome]int
+	// Total holds the number of each outcome across all verbs.
+	Total map[Outcome]int
+	// Rows is the number of resources in the matrix.
+	Rows int
+	// WriteRows is the number of resources with write access for any verb.
+	WriteRows int
+}
+
+// NewSummary creates a Summary for the given verbs without any outcomes.
+func NewSummary(verbs []string) *Summary {
+	s := &Summary{
+		Verbs:  verbs,
+		Counts: make(map[string]map[Outcome]int, len(verbs)),
+		Total:  make(map[Outcome]int),
+	}
+	for _, v := range verbs {
+		s.Counts[v] = make(map[Outcome]int)
+	}
+	return s
+}
+
+// Add counts the outcome for the given verb.
+func (s *Summary) Add(verb string, o Outcome) {
+	s.Counts[verb][o]++
+	s.Total[o]++
+}
+
+// Render prints the summary as a table with one line per verb, followed by
+// the totals and the share of resources with write access. Cancelled access
+// reviews are only shown in a column of their own, if there are any.
+func (s *Summary) Render(out io.Writer) {
+	w := tabwriter.NewWriter(out, 4, 8, 2, ' ', 0)
+
+	cancelled := s.Total[Cancel] > 0
+	header := "VERB\tALLOWED\tDENIED\tN/A\tERRORS"
+	if cancelled {
+		header += "\tCANCELLED"
+	}
+	fmt.Fprintln(w, header)
+	for _, v := range s.Verbs {
+		fmt.Fprintln(w, summaryLine(strings.ToUpper(v), s.Counts[v], cancelled))
+	}
+	fmt.Fprintln(w, summaryLine("TOTAL", s.Total, cancelled))
+	w.Flush()
+
+	var share float64
+	if s.Rows > 0 {
+		share = 100 * float64(s.WriteRows) / float64(s.Rows)
+	}
+	fmt.Fprintf(out, "Write access to %d of %d resources (%.1f%%)\n", s.WriteRows, s.Rows, share)
+}
+
+func summaryLine(name string, counts map[Outcome]int, cancelled bool) string {
+	line := fmt.Sprintf("%s\t%d\t%d\t%d\t%d", name, counts[Up], counts[Down]+counts[Deny], counts[None], counts[Err])
+	if cancelled {
+		line += fmt.Sprintf("\t%d", counts[Cancel])
+	}
+	return line
+}
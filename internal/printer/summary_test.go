@@ -0,0 +1,43 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary_Cancelled(t *testing.T) {
+	s := NewSummary([]string{"list", "delete"})
+	s.Rows = 2
+	s.Add("list", Up)
+	s.Add("list", Err)
+	s.Add("delete", Down)
+	s.Add("delete", Cancel)
+
+	buf := &bytes.Buffer{}
+	s.Render(buf)
+
+	assert.Equal(t, `VERB    ALLOWED  DENIED  N/A  ERRORS  CANCELLED
+LIST    1        0       0    1       0
+DELETE  0        1       0    0       1
+TOTAL   1        1       0    1       1
+Write access to 0 of 2 resources (0.0%)
+`, buf.String())
+}
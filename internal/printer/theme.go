@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	// for testing
+	lookupEnv = os.LookupEnv
+)
+
+// Style is the appearance of an outcome in the icon table.
+type Style struct {
+	Symbol string
+	Color  color
+}
+
+// Theme assigns a style to every outcome.
+type Theme map[Outcome]Style
+
+var defaultTheme = Theme{
+	None:   {Symbol: "", Color: none},
+	Up:     {Symbol: "✔", Color: green}, // ✓
+	Down:   {Symbol: "✖", Color: red},   // ✕
+	Deny:   {Symbol: "⊘", Color: yellow},
+	Err:    {Symbol: "ERR", Color: purple},
+	Cancel: {Symbol: "…", Color: none},
+}
+
+// themeOutcomes maps the names which can be used in a theme setting to outcomes.
+var themeOutcomes = map[string]Outcome{
+	"allowed":           Up,
+	"denied":            Down,
+	"explicitly-denied": Deny,
+	"error":             Err,
+	"cancelled":         Cancel,
+	"not-applicable":    None,
+}
+
+var colorNames = map[string]color{
+	"none":    none,
+	"black":   color(30),
+	"red":     red,
+	"green":   green,
+	"yellow":  yellow,
+	"blue":    color(34),
+	"magenta": purple,
+	"purple":  purple,
+	"cyan":    color(36),
+	"white":   color(37),
+}
+
+// ParseTheme creates a theme from settings of the form
+// 'outcome=symbol[:color]', e.g. 'allowed=Y:green'. Outcomes without a
+// setting keep their default style. The color is either a name, such as
+// 'red', or an ANSI color code.
+func ParseTheme(settings []string) (Theme, error) {
+	theme := make(Theme, len(defaultTheme))
+	for o, s := range defaultTheme {
+		theme[o] = s
+	}
+
+	for _, setting := range settings {
+		parts := strings.SplitN(setting, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("theme setting must have the form outcome=symbol[:color], got %q", setting)
+		}
+		o, ok := themeOutcomes[parts[0]]
+		if !ok {
+			return nil, fmt.Errorf("unexpected outcome %q in theme setting", parts[0])
+		}
+
+		style := theme[o]
+		style.Symbol = parts[1]
+		if i := strings.LastIndex(parts[1], ":"); i >= 0 {
+			c, err := parseColor(parts[1][i+1:])
+			if err != nil {
+				return nil, err
+			}
+			style.Symbol, style.Color = parts[1][:i], c
+		}
+		theme[o] = style
+	}
+	return theme, nil
+}
+
+func parseColor(s string) (color, error) {
+	if c, ok := colorNames[s]; ok {
+		return c, nil
+	}
+	if code, err := strconv.Atoi(s); err == nil && code >= 0 && code < 256 {
+		return color(code), nil
+	}
+	return none, fmt.Errorf("unexpected color %q in theme setting", s)
+}
+
+// useColor decides whether to print color codes. Unless the color mode is
+// 'always' or 'never', colors are disabled by NO_COLOR, enabled by
+// FORCE_COLOR, and otherwise only used for terminals.
+func useColor(mode string, out io.Writer) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if noColor, _ := lookupEnv("NO_COLOR"); noColor != "" {
+		return false
+	}
+	if force, _ := lookupEnv("FORCE_COLOR"); force != "" && force != "0" {
+		return true
+	}
+	return isTerminal(out)
+}
+
+// theme returns the configured theme of the table, or the default theme.
+func (p *Table) theme() Theme {
+	if p.Theme == nil {
+		return defaultTheme
+	}
+	return p.Theme
+}
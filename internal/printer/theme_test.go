@@ -0,0 +1,144 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings []string
+		expected map[Outcome]Style
+		err      string
+	}{
+		{
+			name:     "default theme",
+			expected: defaultTheme,
+		},
+		{
+			name:     "symbol only",
+			settings: []string{"allowed=Y"},
+			expected: map[Outcome]Style{Up: {Symbol: "Y", Color: green}},
+		},
+		{
+			name:     "symbol and color",
+			settings: []string{"denied=N:blue", "error=!:208"},
+			expected: map[Outcome]Style{Down: {Symbol: "N", Color: color(34)}, Err: {Symbol: "!", Color: color(208)}},
+		},
+		{
+			name:     "empty symbol",
+			settings: []string{"not-applicable=:none"},
+			expected: map[Outcome]Style{None: {Symbol: "", Color: none}},
+		},
+		{
+			name:     "missing symbol",
+			settings: []string{"allowed"},
+			err:      `theme setting must have the form outcome=symbol[:color], got "allowed"`,
+		},
+		{
+			name:     "unknown outcome",
+			settings: []string{"granted=Y"},
+			err:      `unexpected outcome "granted" in theme setting`,
+		},
+		{
+			name:     "unknown color",
+			settings: []string{"allowed=Y:chartreuse"},
+			err:      `unexpected color "chartreuse" in theme setting`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			theme, err := ParseTheme(test.settings)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			for o, s := range test.expected {
+				assert.Equal(t, s, theme[o])
+			}
+			assert.Len(t, theme, len(defaultTheme))
+		})
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		env      map[string]string
+		terminal bool
+		expected bool
+	}{
+		{name: "auto on terminal", terminal: true, expected: true},
+		{name: "auto without terminal", expected: false},
+		{name: "always", mode: "always", expected: true},
+		{name: "never on terminal", mode: "never", terminal: true, expected: false},
+		{name: "NO_COLOR on terminal", env: map[string]string{"NO_COLOR": "1"}, terminal: true, expected: false},
+		{name: "FORCE_COLOR without terminal", env: map[string]string{"FORCE_COLOR": "1"}, expected: true},
+		{name: "FORCE_COLOR=0 without terminal", env: map[string]string{"FORCE_COLOR": "0"}, expected: false},
+		{name: "NO_COLOR wins over FORCE_COLOR", env: map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, expected: false},
+		{name: "always wins over NO_COLOR", mode: "always", env: map[string]string{"NO_COLOR": "1"}, expected: true},
+	}
+
+	defer func() {
+		lookupEnv = os.LookupEnv
+		isTerminal = isTerminalImpl
+	}()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lookupEnv = func(key string) (string, bool) {
+				value, ok := test.env[key]
+				return value, ok
+			}
+			isTerminal = func(io.Writer) bool { return test.terminal }
+
+			assert.Equal(t, test.expected, useColor(test.mode, &bytes.Buffer{}))
+		})
+	}
+}
+
+func TestRenderTheme(t *testing.T) {
+	theme, err := ParseTheme([]string{"allowed=Y:blue", "denied=N"})
+	assert.NoError(t, err)
+	table := &Table{
+		Headers: []string{"NAME", "GET", "LIST"},
+		Rows: []Row{
+			{Intro: []string{"resource1"}, Entries: []Outcome{Up, Down}},
+		},
+		Color: "always",
+		Theme: theme,
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "icon-table")
+	assert.Equal(t, HEADER+"resource1  \033[34mY\033[0m    \033[31mN\033[0m\n", buf.String())
+
+	table.Color = "never"
+	buf = &bytes.Buffer{}
+	table.Render(buf, "icon-table")
+	assert.Equal(t, HEADER+"resource1  Y    N\n", buf.String())
+}
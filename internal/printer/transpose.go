@@ -0,0 +1,55 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import "strings"
+
+// Transpose swaps the axes of the table, so that every outcome column becomes
+// a row and every row becomes a column. The intro columns of a row are
+// joined to form the new column header.
+func (p *Table) Transpose() *Table {
+	intro := p.introColumns()
+
+	headers := []string{"VERB"}
+	for _, row := range p.Rows {
+		headers = append(headers, rowName(row))
+	}
+
+	t := TableWithHeaders(headers)
+	t.Legend = p.Legend
+	t.Diff = p.Diff
+	t.RotateHeaders = p.RotateHeaders
+	t.Footnotes = p.Footnotes
+
+	for i, h := range p.Headers[intro:] {
+		outcomes := make([]Outcome, 0, len(p.Rows))
+		notes := make([]string, len(p.Rows))
+		hasNotes := false
+		for j, row := range p.Rows {
+			outcomes = append(outcomes, row.Entries[i])
+			if i < len(row.Notes) && row.Notes[i] != "" {
+				notes[j] = row.Notes[i]
+				hasNotes = true
+			}
+		}
+		t.AddRow([]string{strings.ToLower(h)}, outcomes...)
+		if hasNotes {
+			t.Rows[len(t.Rows)-1].Notes = notes
+		}
+	}
+	return t
+}
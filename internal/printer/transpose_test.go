@@ -0,0 +1,67 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranspose(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "KIND", "GET", "LIST", "CREATE"},
+		Rows: []Row{
+			{Intro: []string{"alice", "User"}, Entries: []Outcome{Up, Down, None}},
+			{Intro: []string{"bob", "Group"}, Entries: []Outcome{Err, Up, Down}},
+		},
+		Diff: true,
+	}
+
+	transposed := table.Transpose()
+
+	assert.Equal(t, []string{"VERB", "alice User", "bob Group"}, transposed.Headers)
+	assert.Equal(t, []Row{
+		{Intro: []string{"get"}, Entries: []Outcome{Up, Err}},
+		{Intro: []string{"list"}, Entries: []Outcome{Down, Up}},
+		{Intro: []string{"create"}, Entries: []Outcome{None, Down}},
+	}, transposed.Rows)
+	assert.True(t, transposed.Diff)
+}
+
+func TestRenderRotatedHeaders(t *testing.T) {
+	table := &Table{
+		Headers: []string{"VERB", "pods", "nodes"},
+		Rows: []Row{
+			{Intro: []string{"get"}, Entries: []Outcome{Up, Down}},
+			{Intro: []string{"list"}, Entries: []Outcome{Up, Err}},
+		},
+		RotateHeaders: true,
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "ascii-table")
+	assert.Equal(t, `           n
+      p    o
+      o    d
+      d    e
+VERB  s    s
+get   yes  no
+list  yes  ERR
+`, buf.String())
+}
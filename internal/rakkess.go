@@ -18,29 +18,74 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/corneliusweig/rakkess/internal/client"
 	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/graph"
+	"github.com/corneliusweig/rakkess/internal/metrics"
 	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/corneliusweig/rakkess/internal/risk"
 	"github.com/corneliusweig/rakkess/internal/validation"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 )
 
+const serviceAccountPrefix = "system:serviceaccount:"
+
+var (
+	// for testing
+	now = metav1.Now
+)
+
 // Resource determines the access right of the current (or impersonated) user
 // and prints the result as a matrix with verbs in the horizontal and resource names
 // in the vertical direction.
 func Resource(ctx context.Context, opts *options.RakkessOptions) (result.ResourceAccess, error) {
+	return checkResources(ctx, opts, nil)
+}
+
+// StreamResources is like Resource, but prints the access cells of every
+// resource as JSON lines as soon as the resource has been checked.
+func StreamResources(ctx context.Context, opts *options.RakkessOptions) error {
+	enc := json.NewEncoder(opts.Streams.Out)
+	_, err := checkResources(ctx, opts, func(r result.Resource) {
+		for _, c := range r.Cells(opts.Verbs) {
+			if err := enc.Encode(c); err != nil {
+				klog.Errorf("Could not stream result: %s", err)
+			}
+		}
+	})
+	return err
+}
+
+// checkResources determines the access rights for all available resources.
+// Meanwhile, it shows the progress on the error stream.
+func checkResources(ctx context.Context, opts *options.RakkessOptions, onResult func(result.Resource)) (result.ResourceAccess, error) {
 	if err := validation.Options(opts); err != nil {
 		return nil, err
 	}
 
-	grs, err := client.FetchAvailableGroupResources(opts)
+	fetchOpts := opts
+	if opts.OutputFormat == "sarif" && !opts.Subresources {
+		// some risky grants are on subresources, such as pods/exec
+		o := *opts
+		o.Subresources = true
+		fetchOpts = &o
+	}
+	grs, err := client.FetchAvailableGroupResources(fetchOpts)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetch available group resources")
 	}
+	if fetchOpts != opts {
+		grs = riskySubresources(grs)
+	}
 	klog.V(2).Info(grs)
 
 	authClient, err := opts.GetAuthClient()
@@ -48,17 +93,147 @@ func Resource(ctx context.Context, opts *options.RakkessOptions) (result.Resourc
 		return nil, errors.Wrap(err, "get auth client")
 	}
 
-	ret := client.CheckResourceAccess(ctx, authClient, grs, opts.Verbs, opts.ConfigFlags.Namespace)
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	progress := printer.NewProgress(opts.Streams.ErrOut, len(grs))
+	defer progress.Clear()
+
+	ret := client.CheckResourceAccess(ctx, authClient, grs, opts.Verbs, opts.ConfigFlags.Namespace, opts.Concurrency, opts.Retries, func(r result.Resource) {
+		if onResult != nil {
+			progress.Clear()
+			onResult(r)
+		}
+		progress.Add(r.HasErrors())
+	})
+
+	if err := ctx.Err(); err != nil {
+		progress.Clear()
+		deadline := err == context.DeadlineExceeded
+		n := 0
+		for _, r := range ret {
+			if !r.Incomplete() && !(deadline && r.TimedOut()) {
+				n++
+			}
+		}
+		printIncomplete(opts, deadline, n, len(grs), "resources")
+	}
 	return ret, nil
 }
 
+// NonResourceURLs determines the access rights of the current (or impersonated)
+// user to non-resource URLs such as '/metrics'. If no paths are given, well-known
+// paths of the API server are checked.
+func NonResourceURLs(ctx context.Context, opts *options.RakkessOptions, paths []string) (result.NonResourceAccess, error) {
+	if err := validation.NonResourceOptions(opts); err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		paths = constants.DefaultNonResourceURLs
+	}
+
+	authClient, err := opts.GetAuthClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "get auth client")
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	ret := client.CheckNonResourceAccess(ctx, authClient, paths, opts.Verbs, opts.Retries)
+
+	if err := ctx.Err(); err != nil {
+		deadline := err == context.DeadlineExceeded
+		n := 0
+		for _, u := range ret {
+			if !u.Incomplete() && !(deadline && u.TimedOut()) {
+				n++
+			}
+		}
+		printIncomplete(opts, deadline, n, len(paths), "URLs")
+	}
+	return ret, nil
+}
+
+// PrintNonResourceURLs prints the access matrix of the given non-resource URLs
+// in the configured output format.
+func PrintNonResourceURLs(opts *options.RakkessOptions, na result.NonResourceAccess) error {
+	if printer.IsStructured(opts.OutputFormat) {
+		return printMatrix(opts, na.Matrix(opts.Verbs))
+	}
+	return PrintTable(opts, na.Table(opts.Verbs))
+}
+
+// riskySubresources drops the subresources which are not covered by any risk
+// rule, so that only those need to be checked.
+func riskySubresources(grs []client.GroupResource) []client.GroupResource {
+	var ret []client.GroupResource
+	for _, gr := range grs {
+		if !strings.Contains(gr.APIResource.Name, "/") || risk.Covers(gr.APIGroup, gr.APIResource.Name) {
+			ret = append(ret, gr)
+		}
+	}
+	return ret
+}
+
+// printIncomplete tells on the error stream that only n of total items were
+// checked, because the global timeout expired or the user interrupted.
+func printIncomplete(opts *options.RakkessOptions, deadline bool, n, total int, items string) {
+	reason := "Interrupted"
+	if deadline {
+		reason = fmt.Sprintf("Timed out after %s", opts.Timeout)
+	}
+	fmt.Fprintf(opts.Streams.ErrOut, "%s: the result is incomplete, %d of %d %s were checked.\n", reason, n, total, items)
+}
+
+// PrintResources prints the access matrix of the given resources in the
+// configured output format.
+func PrintResources(opts *options.RakkessOptions, ra result.ResourceAccess) error {
+	if opts.OutputFormat == "sarif" {
+		return risk.PrintSARIF(opts.Streams.Out, risk.FromResources(ra, reviewedSubject(opts)))
+	}
+	if printer.IsStructured(opts.OutputFormat) || metrics.IsFormat(opts.OutputFormat) {
+		return printMatrix(opts, ra.Matrix(opts.Verbs))
+	}
+	if opts.SummaryOnly {
+		ra.Summary(opts.Verbs).Render(opts.Streams.Out)
+		return nil
+	}
+
+	t := ra.Table(opts.Verbs)
+	switch {
+	case printer.IsDelimited(opts.OutputFormat):
+		t = ra.SplitTable(opts.Verbs)
+	case opts.OutputFormat == "wide":
+		t = ra.WideTable(opts.Verbs)
+	}
+	if err := PrintTable(opts, t); err != nil {
+		return err
+	}
+
+	if opts.Summary {
+		fmt.Fprintln(opts.Streams.Out)
+		ra.Summary(opts.Verbs).Render(opts.Streams.Out)
+	}
+	return nil
+}
+
 // Subject determines the subjects with access right to the given resource and
 // prints the result as a matrix with verbs in the horizontal and subject names
 // in the vertical direction.
 func Subject(ctx context.Context, opts *options.RakkessOptions, resource, resourceName string) error {
-	if err := validation.OutputFormat(opts.OutputFormat); err != nil {
+	if err := validation.SubjectOptions(opts); err != nil {
 		return err
 	}
+	if opts.OutputFormat == "junit" {
+		return fmt.Errorf("output format junit is only supported together with --%s", constants.FlagDiffWith)
+	}
 
 	mapper, err := opts.ConfigFlags.ToRESTMapper()
 	if err != nil {
@@ -73,19 +248,95 @@ func Subject(ctx context.Context, opts *options.RakkessOptions, resource, resour
 	if err != nil {
 		return errors.Wrap(err, "get subject access")
 	}
+	subjectAccess.Group = versionedResource.Group
 
 	if subjectAccess.Empty() {
 		klog.Warningf("No subjects with access found. This most likely means that you have insufficient rights to review authorization.")
 		return nil
 	}
 
-	t := subjectAccess.Table(opts.Verbs)
-	t.Render(opts.Streams.Out, opts.OutputFormat)
+	if err := printSubjects(opts, subjectAccess); err != nil {
+		return err
+	}
 
+	hints := opts.Streams.Out
+	if !printer.IsHumanReadable(opts.OutputFormat) {
+		hints = opts.Streams.ErrOut
+	}
 	namespace := opts.ConfigFlags.Namespace
 	if namespace == nil || *namespace == "" {
-		fmt.Fprintf(opts.Streams.Out, "Only ClusterRoleBindings are considered, because no namespace is given.\n")
+		fmt.Fprintf(hints, "Only ClusterRoleBindings are considered, because no namespace is given.\n")
 	}
 
 	return nil
 }
+
+func printSubjects(opts *options.RakkessOptions, sa *result.SubjectAccess) error {
+	if opts.OutputFormat == "sarif" {
+		return risk.PrintSARIF(opts.Streams.Out, risk.FromSubjects(sa))
+	}
+	if graph.IsFormat(opts.OutputFormat) {
+		return graph.FromSubjects(sa, opts.Verbs).Print(opts.Streams.Out, opts.OutputFormat)
+	}
+	if printer.IsStructured(opts.OutputFormat) || metrics.IsFormat(opts.OutputFormat) {
+		return printMatrix(opts, sa.Matrix(opts.Verbs))
+	}
+	return PrintTable(opts, sa.Table(opts.Verbs))
+}
+
+// PrintTable renders the table in the configured output format and layout.
+func PrintTable(opts *options.RakkessOptions, t *printer.Table) error {
+	theme, err := printer.ParseTheme(opts.Theme)
+	if err != nil {
+		return err
+	}
+
+	if opts.SortBy != "" {
+		t.SortBy(opts.SortBy)
+	}
+	if opts.GroupBy == "api-group" {
+		result.GroupByAPIGroup(t)
+	}
+	if opts.Transpose {
+		t = t.Transpose()
+	}
+	t.RotateHeaders = opts.RotateHeaders
+	t.Footnotes = opts.ShowReasons
+	t.Color = opts.Color
+	t.Theme = theme
+	t.Render(opts.Streams.Out, opts.OutputFormat)
+	return nil
+}
+
+// printMatrix records the query parameters in the access matrix and prints it
+// in the configured output format.
+func printMatrix(opts *options.RakkessOptions, m *result.AccessMatrix) error {
+	m.CreationTimestamp = now()
+	m.Spec.Context = opts.CurrentContext()
+	if ns := opts.ConfigFlags.Namespace; ns != nil {
+		m.Spec.Namespace = *ns
+	}
+	if user := opts.ConfigFlags.Impersonate; user != nil && *user != "" {
+		m.Spec.User = *user
+		if strings.HasPrefix(*user, serviceAccountPrefix) {
+			m.Spec.ServiceAccount = strings.TrimPrefix(*user, serviceAccountPrefix)
+		}
+	}
+	if groups := opts.ConfigFlags.ImpersonateGroup; groups != nil {
+		m.Spec.Groups = *groups
+	}
+	if metrics.IsFormat(opts.OutputFormat) {
+		// without impersonation, name the kubeconfig user, so that the
+		// series of different users can be told apart
+		return metrics.Print(opts.Streams.Out, opts.OutputFormat, m, opts.CurrentUser())
+	}
+	return printer.PrintObject(opts.Streams.Out, opts.OutputFormat, m)
+}
+
+// reviewedSubject names the subject whose access rights are reviewed.
+func reviewedSubject(opts *options.RakkessOptions) string {
+	if user := opts.ConfigFlags.Impersonate; user != nil && *user != "" {
+		return *user
+	}
+	return "The current user"
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/client"
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPrintResources_YAML(t *testing.T) {
+	defer func() { now = metav1.Now }()
+	now = func() metav1.Time {
+		return metav1.NewTime(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	}
+
+	opts, _, out, _ := options.NewTestRakkessOptions()
+	opts.OutputFormat = "yaml"
+	opts.Verbs = []string{"list"}
+	context, namespace, user := "some-context", "some-ns", "system:serviceaccount:some-ns:some-sa"
+	opts.ConfigFlags.Context = &context
+	opts.ConfigFlags.Namespace = &namespace
+	opts.ConfigFlags.Impersonate = &user
+
+	ra := result.ResourceAccess{
+		"deployments.apps": {
+			Name:      "deployments",
+			Group:     "apps",
+			Namespace: "some-ns",
+			Access:    map[string]result.Access{"list": result.Allowed},
+		},
+	}
+
+	assert.NoError(t, PrintResources(opts, ra))
+	assert.Equal(t, `apiVersion: rakkess.corneliusweig.github.io/v1alpha1
+kind: AccessMatrix
+metadata:
+  creationTimestamp: "2021-06-01T12:00:00Z"
+spec:
+  context: some-context
+  namespace: some-ns
+  serviceAccount: some-ns:some-sa
+  user: system:serviceaccount:some-ns:some-sa
+  verbs:
+  - list
+status:
+  cells:
+  - group: apps
+    namespace: some-ns
+    resource: deployments
+    state: allowed
+    verb: list
+`, out.String())
+}
+
+func TestPrintResources_JSONPath(t *testing.T) {
+	opts, _, out, _ := options.NewTestRakkessOptions()
+	opts.OutputFormat = `jsonpath={range .status.cells[?(@.state=="allowed")]}{.resource}.{.group} {.verb}{"\n"}{end}`
+	opts.Verbs = []string{"list", "delete"}
+	context := "some-context"
+	opts.ConfigFlags.Context = &context
+
+	ra := result.ResourceAccess{
+		"deployments.apps": {
+			Name:   "deployments",
+			Group:  "apps",
+			Access: map[string]result.Access{"list": result.Allowed, "delete": result.Allowed},
+		},
+		"secrets": {
+			Name:   "secrets",
+			Access: map[string]result.Access{"list": result.Denied, "delete": result.Allowed},
+		},
+	}
+
+	assert.NoError(t, PrintResources(opts, ra))
+	assert.Equal(t, "deployments.apps list\ndeployments.apps delete\nsecrets. delete\n", out.String())
+}
+
+func TestPrintResources_OpenMetrics(t *testing.T) {
+	opts, _, out, _ := options.NewTestRakkessOptions()
+	opts.OutputFormat = "openmetrics"
+	opts.Verbs = []string{"list"}
+	namespace, user := "some-ns", "system:serviceaccount:some-ns:some-sa"
+	opts.ConfigFlags.Namespace = &namespace
+	opts.ConfigFlags.Impersonate = &user
+
+	ra := result.ResourceAccess{
+		"deployments.apps": {
+			Name:      "deployments",
+			Group:     "apps",
+			Namespace: "some-ns",
+			Access:    map[string]result.Access{"list": result.Allowed},
+		},
+	}
+
+	assert.NoError(t, PrintResources(opts, ra))
+	assert.Contains(t, out.String(), `rakkess_access{resource="deployments",group="apps",verb="list",namespace="some-ns",subject="system:serviceaccount:some-ns:some-sa"} 1`+"\n")
+	assert.True(t, strings.HasSuffix(out.String(), "# EOF\n"))
+}
+
+func TestPrintResources_Prometheus(t *testing.T) {
+	opts, _, out, _ := options.NewTestRakkessOptions()
+	opts.OutputFormat = "prometheus"
+	opts.Verbs = []string{"list"}
+
+	ra := result.ResourceAccess{
+		"secrets": {Name: "secrets", Access: map[string]result.Access{"list": result.RequestErr}},
+	}
+
+	assert.NoError(t, PrintResources(opts, ra))
+	assert.Contains(t, out.String(), "# TYPE rakkess_request_errors gauge\n")
+	assert.NotContains(t, out.String(), "# EOF")
+}
+
+func TestPrintResources_SummaryOnly(t *testing.T) {
+	opts, _, out, _ := options.NewTestRakkessOptions()
+	opts.OutputFormat = "icon-table"
+	opts.Verbs = []string{"list", "delete"}
+	opts.SummaryOnly = true
+
+	ra := result.ResourceAccess{
+		"deployments.apps": {
+			Name:   "deployments",
+			Group:  "apps",
+			Access: map[string]result.Access{"list": result.Allowed, "delete": result.Allowed},
+		},
+		"secrets": {
+			Name:   "secrets",
+			Access: map[string]result.Access{"list": result.Denied, "delete": result.RequestErr},
+		},
+		"tokenreviews.authentication.k8s.io": {
+			Name:   "tokenreviews",
+			Group:  "authentication.k8s.io",
+			Access: map[string]result.Access{"list": result.NotApplicable, "delete": result.NotApplicable},
+		},
+	}
+
+	assert.NoError(t, PrintResources(opts, ra))
+	assert.Equal(t, `VERB    ALLOWED  DENIED  N/A  ERRORS
+LIST    1        1       1    0
+DELETE  1        0       1    1
+TOTAL   2        1       2    1
+Write access to 1 of 3 resources (33.3%)
+`, out.String())
+}
+
+func TestSubject_JUnit(t *testing.T) {
+	opts, _, _, _ := options.NewTestRakkessOptions()
+	opts.OutputFormat = "junit"
+	opts.Verbs = []string{"list"}
+
+	err := Subject(context.Background(), opts, "pods", "")
+
+	assert.EqualError(t, err, "output format junit is only supported together with --diff-with")
+}
+
+func TestPrintIncomplete(t *testing.T) {
+	opts, _, _, errOut := options.NewTestRakkessOptions()
+	opts.Timeout = 2 * time.Minute
+
+	printIncomplete(opts, false, 3, 10, "resources")
+	printIncomplete(opts, true, 7, 10, "resources")
+
+	assert.Equal(t, `Interrupted: the result is incomplete, 3 of 10 resources were checked.
+Timed out after 2m0s: the result is incomplete, 7 of 10 resources were checked.
+`, errOut.String())
+}
+
+func TestPrintResources_SARIF(t *testing.T) {
+	opts, _, out, _ := options.NewTestRakkessOptions()
+	opts.OutputFormat = "sarif"
+	opts.Verbs = constants.ValidVerbs
+	namespace := "some-ns"
+	opts.ConfigFlags.Namespace = &namespace
+
+	ra := result.ResourceAccess{
+		"pods": {
+			Name:      "pods",
+			Namespace: "some-ns",
+			Access:    map[string]result.Access{"get": result.Allowed, "create": result.Denied},
+		},
+		"pods/exec": {
+			Name:      "pods/exec",
+			Namespace: "some-ns",
+			Access:    map[string]result.Access{"get": result.Denied, "create": result.Allowed},
+		},
+	}
+
+	assert.NoError(t, PrintResources(opts, ra))
+	assert.Contains(t, out.String(), `"ruleId": "RAK003"`)
+	assert.Contains(t, out.String(), "may create pods/exec in namespace some-ns")
+}
+
+func TestRiskySubresources(t *testing.T) {
+	grs := []client.GroupResource{
+		{APIResource: metav1.APIResource{Name: "pods"}},
+		{APIResource: metav1.APIResource{Name: "pods/exec"}},
+		{APIResource: metav1.APIResource{Name: "pods/log"}},
+		{APIGroup: "apps", APIResource: metav1.APIResource{Name: "deployments/scale"}},
+	}
+
+	var names []string
+	for _, gr := range riskySubresources(grs) {
+		names = append(names, gr.APIResource.Name)
+	}
+	assert.Equal(t, []string{"pods", "pods/exec"}, names)
+}
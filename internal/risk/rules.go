@@ -0,0 +1,266 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Level is the severity of a finding as defined by SARIF.
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelNote    Level = "note"
+)
+
+const rbacGroup = "rbac.authorization.k8s.io"
+
+// Rule describes a risky grant.
+type Rule struct {
+	ID          string
+	Name        string
+	Description string
+	Level       Level
+	// SecuritySeverity is a score between 0.0 and 10.0, which is used by
+	// code-scanning tools to rank findings.
+	SecuritySeverity string
+	// Group and Resources select the resources the rule applies to.
+	Group     string
+	Resources []string
+	// Verbs trigger the rule if any of them is allowed.
+	Verbs []string
+	// Wildcard rules trigger if all verbs are allowed.
+	Wildcard bool
+}
+
+// Rules is the list of known risky grants.
+var Rules = []Rule{
+	{
+		ID:               "RAK001",
+		Name:             "WildcardSecretAccess",
+		Description:      "Unrestricted access to secrets allows to read and replace any credentials.",
+		Level:            LevelError,
+		SecuritySeverity: "9.0",
+		Resources:        []string{"secrets"},
+		Wildcard:         true,
+	},
+	{
+		ID:               "RAK002",
+		Name:             "SecretRead",
+		Description:      "Reading secrets exposes credentials such as service-account tokens.",
+		Level:            LevelError,
+		SecuritySeverity: "7.5",
+		Resources:        []string{"secrets"},
+		Verbs:            []string{"get", "list", "watch"},
+	},
+	{
+		ID:               "RAK003",
+		Name:             "PodExec",
+		Description:      "Executing commands in or attaching to pods grants access to everything the container can access.",
+		Level:            LevelError,
+		SecuritySeverity: "8.0",
+		Resources:        []string{"pods/exec", "pods/attach"},
+		Verbs:            []string{"create", "get"},
+	},
+	{
+		ID:               "RAK004",
+		Name:             "RBACModification",
+		Description:      "Modifying roles or bindings allows to escalate privileges.",
+		Level:            LevelError,
+		SecuritySeverity: "8.5",
+		Group:            rbacGroup,
+		Resources:        []string{"roles", "clusterroles", "rolebindings", "clusterrolebindings"},
+		Verbs:            []string{"create", "update", "patch"},
+	},
+	{
+		ID:               "RAK005",
+		Name:             "PodCreation",
+		Description:      "Creating pods allows to run arbitrary code with any service-account of the namespace.",
+		Level:            LevelWarning,
+		SecuritySeverity: "5.0",
+		Resources:        []string{"pods"},
+		Verbs:            []string{"create"},
+	},
+}
+
+// Covers checks if any rule applies to the given resource, which may be a
+// subresource such as 'pods/exec'.
+func Covers(group, resource string) bool {
+	for _, r := range Rules {
+		if r.Group == group && sets.NewString(r.Resources...).Has(resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// Location identifies a kubernetes object through which access is granted.
+type Location struct {
+	Kind, Name, Namespace string
+}
+
+// Finding is a risky grant of a subject.
+type Finding struct {
+	Rule      Rule
+	Subject   string
+	Group     string
+	Resource  string
+	Namespace string
+	Verbs     []string
+	// Locations are the roles and bindings which grant the access. They are
+	// only known for the subject matrix.
+	Locations []Location
+}
+
+// Message describes the finding in a human-readable way.
+func (f *Finding) Message() string {
+	resource := f.fullName()
+	scope := "cluster-wide"
+	if f.Namespace != "" {
+		scope = fmt.Sprintf("in namespace %s", f.Namespace)
+	}
+	return fmt.Sprintf("%s may %s %s %s. %s", f.Subject, strings.Join(f.Verbs, ", "), resource, scope, f.Rule.Description)
+}
+
+// fullName includes the API group of the resource, e.g. 'roles.rbac.authorization.k8s.io'.
+func (f *Finding) fullName() string {
+	if f.Group == "" {
+		return f.Resource
+	}
+	return fmt.Sprintf("%s.%s", f.Resource, f.Group)
+}
+
+// matches determines the verbs which trigger the rule. The allowed verbs
+// must include all verbs to trigger a wildcard rule, so that all verbs need
+// to be checked. For the subject matrix, the verbs come from the role rules
+// where '*' is expanded to all verbs.
+func (r *Rule) matches(group, resource string, allowed sets.String) []string {
+	if r.Group != group || !sets.NewString(r.Resources...).Has(resource) {
+		return nil
+	}
+	if r.Wildcard {
+		if allowed.HasAll(constants.ValidVerbs...) {
+			return constants.ValidVerbs
+		}
+		return nil
+	}
+	var verbs []string
+	for _, v := range r.Verbs {
+		if allowed.Has(v) {
+			verbs = append(verbs, v)
+		}
+	}
+	return verbs
+}
+
+// FromResources finds risky grants in the access matrix of the given subject.
+func FromResources(ra result.ResourceAccess, subject string) []Finding {
+	names := make([]string, 0, len(ra))
+	for name := range ra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var findings []Finding
+	for _, rule := range Rules {
+		for _, name := range names {
+			r := ra[name]
+			allowed := sets.NewString()
+			for verb, a := range r.Access {
+				if a == result.Allowed {
+					allowed.Insert(verb)
+				}
+			}
+			if verbs := rule.matches(r.Group, r.Name, allowed); len(verbs) > 0 {
+				findings = append(findings, Finding{
+					Rule:      rule,
+					Subject:   subject,
+					Group:     r.Group,
+					Resource:  r.Name,
+					Namespace: r.Namespace,
+					Verbs:     verbs,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// FromSubjects finds risky grants in the subject matrix. Each finding points
+// to the roles and bindings which grant the access.
+func FromSubjects(sa *result.SubjectAccess) []Finding {
+	var findings []Finding
+	for _, rule := range Rules {
+		for _, s := range sa.Subjects() {
+			verbs := rule.matches(sa.Group, sa.Resource, sa.Verbs(s))
+			if len(verbs) == 0 {
+				continue
+			}
+			findings = append(findings, Finding{
+				Rule:      rule,
+				Subject:   subjectName(s),
+				Group:     sa.Group,
+				Resource:  sa.Resource,
+				Namespace: sa.Namespace,
+				Verbs:     verbs,
+				Locations: locations(sa, s, verbs),
+			})
+		}
+	}
+	return findings
+}
+
+// locations collects the roles and bindings which grant any of the verbs to
+// the subject.
+func locations(sa *result.SubjectAccess, s result.SubjectRef, verbs []string) []Location {
+	seen := make(map[Location]bool)
+	var locs []Location
+	add := func(l Location) {
+		if !seen[l] {
+			seen[l] = true
+			locs = append(locs, l)
+		}
+	}
+
+	for _, g := range sa.Grants(s) {
+		if !sa.RoleVerbs(g.Role).HasAny(verbs...) {
+			continue
+		}
+		role := Location{Kind: g.Role.Kind, Name: g.Role.Name}
+		if g.Role.Kind == "Role" {
+			role.Namespace = g.Binding.Namespace
+		}
+		add(role)
+		add(Location{Kind: g.Binding.Kind, Name: g.Binding.Name, Namespace: g.Binding.Namespace})
+	}
+	return locs
+}
+
+func subjectName(s result.SubjectRef) string {
+	if s.Namespace == "" {
+		return fmt.Sprintf("%s %s", s.Kind, s.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", s.Kind, s.Namespace, s.Name)
+}
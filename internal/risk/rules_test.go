@@ -0,0 +1,118 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package risk
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/rbac/v1"
+)
+
+func ruleIDs(findings []Finding) []string {
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.Rule.ID+":"+f.fullName())
+	}
+	return ids
+}
+
+func TestFromResources(t *testing.T) {
+	all := make(map[string]result.Access)
+	for _, v := range constants.ValidVerbs {
+		all[v] = result.Allowed
+	}
+
+	tests := []struct {
+		name     string
+		ra       result.ResourceAccess
+		expected []string
+	}{
+		{
+			name: "no risky grants",
+			ra: result.ResourceAccess{
+				"configmaps": {Name: "configmaps", Access: all},
+				"secrets":    {Name: "secrets", Access: map[string]result.Access{"get": result.Denied, "create": result.Allowed}},
+			},
+		},
+		{
+			name: "secrets readable",
+			ra: result.ResourceAccess{
+				"secrets": {Name: "secrets", Access: map[string]result.Access{"get": result.Denied, "list": result.Allowed}},
+			},
+			expected: []string{"RAK002:secrets"},
+		},
+		{
+			name: "wildcard access to secrets",
+			ra: result.ResourceAccess{
+				"secrets": {Name: "secrets", Access: all},
+			},
+			expected: []string{"RAK001:secrets", "RAK002:secrets"},
+		},
+		{
+			name: "rbac modification needs matching group",
+			ra: result.ResourceAccess{
+				"roles.rbac.authorization.k8s.io": {Name: "roles", Group: "rbac.authorization.k8s.io", Access: map[string]result.Access{"patch": result.Allowed}},
+				"roles.example.com":               {Name: "roles", Group: "example.com", Access: map[string]result.Access{"patch": result.Allowed}},
+			},
+			expected: []string{"RAK004:roles.rbac.authorization.k8s.io"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			findings := FromResources(test.ra, "some-user")
+			assert.Equal(t, test.expected, ruleIDs(findings))
+		})
+	}
+}
+
+func TestFromSubjects(t *testing.T) {
+	sa := result.NewSubjectAccess("secrets", "")
+	sa.Namespace = "some-ns"
+	reader := result.RoleRef{Name: "secret-reader", Kind: "Role"}
+	writer := result.RoleRef{Name: "secret-writer", Kind: "ClusterRole"}
+	sa.MatchRules(reader, v1.PolicyRule{Resources: []string{"secrets"}, Verbs: []string{"get"}})
+	sa.MatchRules(writer, v1.PolicyRule{Resources: []string{"secrets"}, Verbs: []string{"create"}})
+	subjects := []v1.Subject{{Kind: "ServiceAccount", Name: "some-sa", Namespace: "some-ns"}}
+	sa.ResolveBinding(result.BindingRef{Name: "read-secrets", Kind: "RoleBinding", Namespace: "some-ns"}, reader, subjects)
+	sa.ResolveBinding(result.BindingRef{Name: "write-secrets", Kind: "ClusterRoleBinding"}, writer, subjects)
+
+	findings := FromSubjects(sa)
+
+	assert.Len(t, findings, 1)
+	f := findings[0]
+	assert.Equal(t, "RAK002", f.Rule.ID)
+	assert.Equal(t, "ServiceAccount some-ns/some-sa", f.Subject)
+	assert.Equal(t, []string{"get"}, f.Verbs)
+	assert.Equal(t, []Location{
+		{Kind: "Role", Name: "secret-reader", Namespace: "some-ns"},
+		{Kind: "RoleBinding", Name: "read-secrets", Namespace: "some-ns"},
+	}, f.Locations)
+	assert.Equal(t, "ServiceAccount some-ns/some-sa may get secrets in namespace some-ns. "+f.Rule.Description, f.Message())
+}
+
+func TestFromSubjects_WildcardRule(t *testing.T) {
+	sa := result.NewSubjectAccess("secrets", "")
+	admin := result.RoleRef{Name: "secret-admin", Kind: "ClusterRole"}
+	sa.MatchRules(admin, v1.PolicyRule{Resources: []string{"secrets"}, Verbs: []string{"*"}})
+	sa.ResolveBinding(result.BindingRef{Name: "admin", Kind: "ClusterRoleBinding"}, admin, []v1.Subject{{Kind: "User", Name: "alice"}})
+
+	assert.Equal(t, []string{"RAK001:secrets", "RAK002:secrets"}, ruleIDs(FromSubjects(sa)))
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package risk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// kubernetesAPI is the base of all artifact locations. The locations are
+	// relative paths of the RBAC objects on the API server.
+	kubernetesAPI = "KUBERNETES_API"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifProperties    `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level Level `json:"level"`
+}
+
+type sarifProperties struct {
+	SecuritySeverity string   `json:"security-severity"`
+	Tags             []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     Level           `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// PrintSARIF prints the findings as SARIF 2.1.0 log.
+func PrintSARIF(out io.Writer, findings []Finding) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           constants.CommandName,
+			InformationURI: "https://github.com/corneliusweig/rakkess",
+			Version:        version.GetBuildInfo().Version,
+		}},
+		Results: []sarifResult{},
+	}
+
+	index := make(map[string]int)
+	for i, r := range Rules {
+		index[r.ID] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   r.ID,
+			Name:                 r.Name,
+			ShortDescription:     sarifMessage{Text: r.Description},
+			DefaultConfiguration: sarifConfiguration{Level: r.Level},
+			Properties: sarifProperties{
+				SecuritySeverity: r.SecuritySeverity,
+				Tags:             []string{"security", "rbac"},
+			},
+		})
+	}
+
+	for i := range findings {
+		f := &findings[i]
+		res := sarifResult{
+			RuleID:    f.Rule.ID,
+			RuleIndex: index[f.Rule.ID],
+			Level:     f.Rule.Level,
+			Message:   sarifMessage{Text: f.Message()},
+		}
+		for _, l := range f.Locations {
+			res.Locations = append(res.Locations, sarifLocation{
+				PhysicalLocation: &sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: apiPath(l), URIBaseID: kubernetesAPI},
+				},
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               l.Name,
+					FullyQualifiedName: qualifiedName(l.Kind, l.Namespace, l.Name),
+					Kind:               "resource",
+				}},
+			})
+		}
+		if len(res.Locations) == 0 {
+			// the granting objects are unknown, so point at the resource under review
+			res.Locations = []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               f.fullName(),
+					FullyQualifiedName: qualifiedName("", f.Namespace, f.fullName()),
+					Kind:               "resource",
+				}},
+			}}
+		}
+		run.Results = append(run.Results, res)
+	}
+
+	data, err := json.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
+
+// apiPath is the path of an RBAC object relative to the API server.
+func apiPath(l Location) string {
+	path := []string{"apis", rbacGroup, "v1"}
+	if l.Namespace != "" {
+		path = append(path, "namespaces", l.Namespace)
+	}
+	path = append(path, strings.ToLower(l.Kind)+"s", l.Name)
+	return strings.Join(path, "/")
+}
+
+func qualifiedName(kind, namespace, name string) string {
+	var parts []string
+	for _, p := range []string{kind, namespace, name} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, "/")
+}
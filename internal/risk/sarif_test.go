@@ -0,0 +1,71 @@
+/*
+Copyright 2021 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package risk
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintSARIF(t *testing.T) {
+	findings := []Finding{
+		{
+			Rule:      Rules[1],
+			Subject:   "User alice",
+			Resource:  "secrets",
+			Namespace: "some-ns",
+			Verbs:     []string{"get"},
+			Locations: []Location{
+				{Kind: "ClusterRole", Name: "secret-reader"},
+				{Kind: "RoleBinding", Name: "read-secrets", Namespace: "some-ns"},
+			},
+		},
+		{
+			Rule:     Rules[3],
+			Subject:  "User bob",
+			Group:    "rbac.authorization.k8s.io",
+			Resource: "roles",
+			Verbs:    []string{"create"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, PrintSARIF(buf, findings))
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Len(t, run.Tool.Driver.Rules, len(Rules))
+	assert.Len(t, run.Results, 2)
+
+	res := run.Results[0]
+	assert.Equal(t, "RAK002", res.RuleID)
+	assert.Equal(t, 1, res.RuleIndex)
+	assert.Equal(t, LevelError, res.Level)
+	assert.Equal(t, "apis/rbac.authorization.k8s.io/v1/clusterroles/secret-reader", res.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "apis/rbac.authorization.k8s.io/v1/namespaces/some-ns/rolebindings/read-secrets", res.Locations[1].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "RoleBinding/some-ns/read-secrets", res.Locations[1].LogicalLocations[0].FullyQualifiedName)
+
+	res = run.Results[1]
+	assert.Nil(t, res.Locations[0].PhysicalLocation)
+	assert.Equal(t, "roles.rbac.authorization.k8s.io", res.Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
@@ -18,20 +18,87 @@ package validation
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/corneliusweig/rakkess/internal/constants"
 	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/corneliusweig/rakkess/internal/printer"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 // Options validates RakkessOptions. Fields validated:
 // - OutputFormat
 // - Verbs
+// - SortBy
+// - GroupBy
+// - Color
+// - Theme
+// - Transpose
 func Options(opts *options.RakkessOptions) error {
-	if err := verbs(opts.Verbs); err != nil {
+	if err := verbs(opts.Verbs, constants.ValidVerbs); err != nil {
 		return err
 	}
-	return OutputFormat(opts.OutputFormat)
+	return layout(opts)
+}
+
+// SubjectOptions is like Options, but also rejects layouts which need the
+// API groups of the rows, because the subject matrix has none.
+func SubjectOptions(opts *options.RakkessOptions) error {
+	if err := Options(opts); err != nil {
+		return err
+	}
+	return ungrouped(opts)
+}
+
+// NonResourceOptions is like SubjectOptions, but validates the verbs against
+// the verbs of non-resource URLs, and rejects the output formats which only
+// apply to resources.
+func NonResourceOptions(opts *options.RakkessOptions) error {
+	if err := verbs(opts.Verbs, constants.ValidNonResourceVerbs); err != nil {
+		return err
+	}
+	if err := layout(opts); err != nil {
+		return err
+	}
+	switch opts.OutputFormat {
+	case "wide", "compact", "junit", "sarif", "dot", "graph-json", "openmetrics", "prometheus":
+		return fmt.Errorf("output format %s is not supported for non-resource URLs", opts.OutputFormat)
+	}
+	return ungrouped(opts)
+}
+
+// ungrouped rejects the layouts which need the API groups of the rows.
+func ungrouped(opts *options.RakkessOptions) error {
+	if opts.GroupBy != "" {
+		return fmt.Errorf("--%s is only supported for the access matrix of all resources", constants.FlagGroupBy)
+	}
+	if opts.SortBy == "group" {
+		return fmt.Errorf("--%s group is only supported for the access matrix of all resources", constants.FlagSortBy)
+	}
+	return nil
+}
+
+// layout validates the fields of RakkessOptions which control the output.
+func layout(opts *options.RakkessOptions) error {
+	if err := oneOf("sort key", opts.SortBy, constants.ValidSortKeys); err != nil {
+		return err
+	}
+	if err := oneOf("grouping", opts.GroupBy, constants.ValidGroupings); err != nil {
+		return err
+	}
+	if err := oneOf("color mode", opts.Color, constants.ValidColorModes); err != nil {
+		return err
+	}
+	if _, err := printer.ParseTheme(opts.Theme); err != nil {
+		return err
+	}
+	if err := OutputFormat(opts.OutputFormat); err != nil {
+		return err
+	}
+	if opts.Transpose && opts.OutputFormat == "compact" {
+		return fmt.Errorf("--%s cannot be combined with output format compact, because the permission strings need verbs as columns", constants.FlagTranspose)
+	}
+	return nil
 }
 
 func OutputFormat(format string) error {
@@ -40,11 +107,42 @@ func OutputFormat(format string) error {
 			return nil
 		}
 	}
+	for _, t := range constants.ValidTemplateFormats {
+		if strings.HasPrefix(format, t+"=") {
+			return nil
+		}
+	}
 	return fmt.Errorf("unexpected output format: %s", format)
 }
 
-func verbs(verbs []string) error {
-	valid := sets.NewString(constants.ValidVerbs...)
+// RateLimits validates the concurrency and the client rate limits of
+// RakkessOptions.
+func RateLimits(opts *options.RakkessOptions) error {
+	if opts.Concurrency < 0 {
+		return fmt.Errorf("--%s must not be negative, got %d", constants.FlagConcurrency, opts.Concurrency)
+	}
+	if opts.QPS <= 0 {
+		return fmt.Errorf("--%s must be positive, got %v", constants.FlagQPS, opts.QPS)
+	}
+	if opts.Burst < 1 {
+		return fmt.Errorf("--%s must be at least 1, got %d", constants.FlagBurst, opts.Burst)
+	}
+	return nil
+}
+
+// Retries validates the retries and the timeout of RakkessOptions.
+func Retries(opts *options.RakkessOptions) error {
+	if opts.Retries < 0 {
+		return fmt.Errorf("--%s must not be negative, got %d", constants.FlagRetries, opts.Retries)
+	}
+	if opts.Timeout < 0 {
+		return fmt.Errorf("--%s must not be negative, got %s", constants.FlagTimeout, opts.Timeout)
+	}
+	return nil
+}
+
+func verbs(verbs, validVerbs []string) error {
+	valid := sets.NewString(validVerbs...)
 	given := sets.NewString(verbs...)
 	difference := given.Difference(valid)
 
@@ -54,3 +152,16 @@ func verbs(verbs []string) error {
 
 	return nil
 }
+
+// oneOf checks that the value is one of the valid values, if it is given at all.
+func oneOf(what, value string, valid []string) error {
+	if value == "" {
+		return nil
+	}
+	for _, v := range valid {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("unexpected %s: %s", what, value)
+}
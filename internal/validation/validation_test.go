@@ -18,7 +18,10 @@ package validation
 
 import (
 	"testing"
+	"time"
 
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/options"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -32,6 +35,15 @@ func TestOutputFormat(t *testing.T) {
 			name:   "valid format",
 			format: "icon-table",
 		},
+		{
+			name:   "valid template format",
+			format: "jsonpath={.status}",
+		},
+		{
+			name:     "template format without template",
+			format:   "go-template",
+			expected: "unexpected output format: go-template",
+		},
 		{
 			name:     "invalid format",
 			format:   "cassowary",
@@ -75,7 +87,172 @@ func TestVerbs(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual := verbs(test.verbs)
+			actual := verbs(test.verbs, constants.ValidVerbs)
+			if test.expected != "" {
+				assert.EqualError(t, actual, test.expected)
+			} else {
+				assert.NoError(t, actual)
+			}
+		})
+	}
+}
+
+func TestOneOf(t *testing.T) {
+	valid := []string{"name", "group"}
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name: "not given",
+		},
+		{
+			name:  "valid value",
+			value: "group",
+		},
+		{
+			name:     "invalid value",
+			value:    "kind",
+			expected: "unexpected sort key: kind",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := oneOf("sort key", test.value, valid)
+			if test.expected != "" {
+				assert.EqualError(t, actual, test.expected)
+			} else {
+				assert.NoError(t, actual)
+			}
+		})
+	}
+}
+
+func TestVerbs_NonResource(t *testing.T) {
+	assert.NoError(t, verbs([]string{"get", "post", "head"}, constants.ValidNonResourceVerbs))
+	assert.EqualError(t, verbs([]string{"get", "list"}, constants.ValidNonResourceVerbs), "unexpected verbs: [list]")
+}
+
+func TestOptions_TransposeCompact(t *testing.T) {
+	opts := &options.RakkessOptions{Verbs: []string{"list"}, OutputFormat: "compact", Transpose: true}
+	assert.EqualError(t, Options(opts), "--transpose cannot be combined with output format compact, because the permission strings need verbs as columns")
+
+	opts.Transpose = false
+	assert.NoError(t, Options(opts))
+}
+
+func TestSubjectOptions(t *testing.T) {
+	opts := &options.RakkessOptions{Verbs: []string{"list"}, OutputFormat: "icon-table", SortBy: "name"}
+	assert.NoError(t, SubjectOptions(opts))
+
+	opts.SortBy = "group"
+	assert.EqualError(t, SubjectOptions(opts), "--sort-by group is only supported for the access matrix of all resources")
+
+	opts.SortBy = ""
+	opts.GroupBy = "api-group"
+	assert.EqualError(t, SubjectOptions(opts), "--group-by is only supported for the access matrix of all resources")
+	assert.NoError(t, Options(opts))
+}
+
+func TestNonResourceOptions(t *testing.T) {
+	opts := &options.RakkessOptions{Verbs: []string{"get", "head"}, OutputFormat: "icon-table"}
+	assert.NoError(t, NonResourceOptions(opts))
+
+	opts.GroupBy = "api-group"
+	assert.EqualError(t, NonResourceOptions(opts), "--group-by is only supported for the access matrix of all resources")
+
+	opts.GroupBy = ""
+	for _, format := range []string{"wide", "compact", "junit", "prometheus"} {
+		opts.OutputFormat = format
+		assert.EqualError(t, NonResourceOptions(opts), "output format "+format+" is not supported for non-resource URLs")
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		qps         float32
+		burst       int
+		expected    string
+	}{
+		{
+			name:        "valid limits",
+			concurrency: 1,
+			qps:         0.5,
+			burst:       1,
+		},
+		{
+			name:        "unbounded concurrency",
+			concurrency: 0,
+			qps:         5,
+			burst:       10,
+		},
+		{
+			name:        "negative concurrency",
+			concurrency: -1,
+			qps:         5,
+			burst:       10,
+			expected:    "--concurrency must not be negative, got -1",
+		},
+		{
+			name:        "negative qps",
+			concurrency: 1,
+			qps:         -1,
+			burst:       10,
+			expected:    "--qps must be positive, got -1",
+		},
+		{
+			name:        "no burst",
+			concurrency: 1,
+			qps:         5,
+			expected:    "--burst must be at least 1, got 0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := &options.RakkessOptions{Concurrency: test.concurrency, QPS: test.qps, Burst: test.burst}
+			actual := RateLimits(opts)
+			if test.expected != "" {
+				assert.EqualError(t, actual, test.expected)
+			} else {
+				assert.NoError(t, actual)
+			}
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		retries  int
+		timeout  time.Duration
+		expected string
+	}{
+		{
+			name:    "valid settings",
+			retries: 3,
+			timeout: time.Minute,
+		},
+		{
+			name:     "negative retries",
+			retries:  -1,
+			expected: "--retries must not be negative, got -1",
+		},
+		{
+			name:     "negative timeout",
+			timeout:  -time.Second,
+			expected: "--timeout must not be negative, got -1s",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := &options.RakkessOptions{Retries: test.retries, Timeout: test.timeout}
+			actual := Retries(opts)
 			if test.expected != "" {
 				assert.EqualError(t, actual, test.expected)
 			} else {